@@ -16,41 +16,62 @@ __todoist_select_multi() {
 	fzf -m
 }
 
+# keep the rows starting with an id, without headers such as sections of items
+__todoist_id_rows() {
+	awk '$1 ~ /^[0-9a-f-]+$/'
+}
+
 __todoist_filter_ids() {
-	COMPREPLY=( $(todoist filter list | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
+	COMPREPLY=( $(todoist filter list | __todoist_id_rows | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
 }
 
 __todoist_item_id() {
-	COMPREPLY=( $(todoist item list | __todoist_select_one | awk '{print $1}') )
+	COMPREPLY=( $(todoist item list | __todoist_id_rows | __todoist_select_one | awk '{print $1}') )
 }
 
 __todoist_item_ids() {
-	COMPREPLY=( $(todoist item list | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
+	COMPREPLY=( $(todoist item list | __todoist_id_rows | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
 }
 
 __todoist_label_id() {
-	COMPREPLY=( $(todoist label list | __todoist_select_one | awk '{print $1}') )
+	COMPREPLY=( $(todoist label list | __todoist_id_rows | __todoist_select_one | awk '{print $1}') )
 }
 
 __todoist_labels_ids() {
-	COMPREPLY=( $(todoist label list | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
+	COMPREPLY=( $(todoist label list | __todoist_id_rows | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
 }
 
 __todoist_project_id() {
-	COMPREPLY=( $(todoist project list | __todoist_select_one | awk '{print $1}') )
+	COMPREPLY=( $(todoist project list | __todoist_id_rows | __todoist_select_one | awk '{print $1}') )
 }
 
 __todoist_project_ids() {
-	COMPREPLY=( $(todoist project list | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
+	COMPREPLY=( $(todoist project list | __todoist_id_rows | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
+}
+
+__todoist_section_id() {
+	COMPREPLY=( $(todoist section list | __todoist_id_rows | __todoist_select_one | awk '{print $1}') )
+}
+
+__todoist_reminder_ids() {
+	COMPREPLY=( $(todoist reminder list | __todoist_id_rows | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
+}
+
+__todoist_notification_ids() {
+	COMPREPLY=( $(todoist notifications | __todoist_id_rows | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
+}
+
+__todoist_queue_uuids() {
+	COMPREPLY=( $(todoist queue list | __todoist_id_rows | __todoist_select_multi | awk '{print $1}' | tr '\n' ' ') )
 }
 
 __todoist_custom_func() {
 	case ${last_command} in
-		todoist_filter_update | todoist_filter_delete)
+		todoist_filter_show | todoist_filter_update | todoist_filter_delete)
 			__todoist_filter_ids
 			return
 			;;
-		todoist_item_update | todoist_item_delete | todoist_item_move | todoist_item_complete | todoist_item_uncomplete)
+		todoist_item_update | todoist_item_delete | todoist_item_move | todoist_item_assign | todoist_item_complete | todoist_item_uncomplete)
 			__todoist_item_ids
 			return
 			;;
@@ -58,10 +79,30 @@ __todoist_custom_func() {
 			__todoist_label_id
 			return
 			;;
-		todoist_project_update | todoist_project_delete | todoist_project_archive | todoist_project_unarchive)
+		todoist_project_update | todoist_project_delete | todoist_project_archive | todoist_project_unarchive | todoist_project_share | todoist_project_unshare)
 			__todoist_project_id
 			return
 			;;
+		todoist_section_update | todoist_section_move | todoist_section_archive | todoist_section_unarchive | todoist_section_delete)
+			__todoist_section_id
+			return
+			;;
+		todoist_reminder_list | todoist_reminder_add | todoist_today_reorder)
+			__todoist_item_ids
+			return
+			;;
+		todoist_reminder_delete)
+			__todoist_reminder_ids
+			return
+			;;
+		todoist_notifications_read | todoist_notifications_unread | todoist_notifications_accept | todoist_notifications_reject)
+			__todoist_notification_ids
+			return
+			;;
+		todoist_queue_drop)
+			__todoist_queue_uuids
+			return
+			;;
 		*)
 			;;
 	esac
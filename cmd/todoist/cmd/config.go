@@ -19,7 +19,7 @@ var configCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dir := os.ExpandEnv("$HOME/.go-todoist")
 		if _, err := os.Stat(dir); err != nil {
-			if err = os.MkdirAll(dir, 0755); err != nil {
+			if err = os.MkdirAll(dir, 0700); err != nil {
 				return err
 			}
 		}
@@ -45,7 +45,7 @@ var configCmd = &cobra.Command{
 		if b, err := json.MarshalIndent(c, "", "  "); err != nil {
 			return err
 		} else {
-			if err = ioutil.WriteFile(file, b, 0644); err != nil {
+			if err = ioutil.WriteFile(file, b, 0600); err != nil {
 				return err
 			}
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/kobtea/go-todoist/todoist"
 	"github.com/spf13/cobra"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -27,11 +28,92 @@ var filterListCmd = &cobra.Command{
 			return err
 		}
 		filters := client.Filter.GetAll()
-		fmt.Println(util.FilterTableString(filters))
-		return nil
+		return printList(func() string {
+			return util.FilterTableString(filters)
+		}, util.FilterFields, util.NewFilterRecords(filters))
+	},
+}
+
+var filterShowCmd = &cobra.Command{
+	Use:   "show [id|name]",
+	Short: "show items matching the filter",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return errors.New("require filter id or name to show")
+		}
+		filter, err := resolveFilter(client, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		views, err := client.Filter.Query(filter.Query)
+		if err != nil {
+			return err
+		}
+		return printFilterViews(client, views)
 	},
 }
 
+// resolveFilter resolves the filter by the id or the name.
+// A part of the name is enough if only one filter matches it.
+func resolveFilter(client *todoist.Client, idOrName string) (*todoist.Filter, error) {
+	if id, err := todoist.NewID(idOrName); err == nil {
+		if filter := client.Filter.Resolve(id); filter != nil {
+			return filter, nil
+		}
+	}
+	filters := client.Filter.FindByName(idOrName)
+	for _, filter := range filters {
+		if filter.Name == idOrName {
+			return &filter, nil
+		}
+	}
+	switch len(filters) {
+	case 0:
+		return nil, fmt.Errorf("no such filter: %s", idOrName)
+	case 1:
+		return &filters[0], nil
+	}
+	var names []string
+	for _, filter := range filters {
+		names = append(names, filter.Name)
+	}
+	return nil, fmt.Errorf("ambiguous filter %s: %s", idOrName, strings.Join(names, ", "))
+}
+
+// printFilterViews prints the items of each view sorted by the due date,
+// with the query of the view as a heading if there are several views.
+// The query is the view field of each record in the structured output.
+func printFilterViews(client *todoist.Client, views []todoist.FilterView) error {
+	var tables []string
+	var records []util.Record
+	for _, view := range views {
+		items := view.Items
+		sort.SliceStable(items, func(i, j int) bool {
+			di, dj := items[i].Due.Date, items[j].Due.Date
+			if di.IsZero() || dj.IsZero() {
+				return !di.IsZero()
+			}
+			return di.Before(dj)
+		})
+		relations := client.Relation.Items(items)
+		table := util.ItemTableString(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date })
+		if len(views) > 1 {
+			table = view.Query + "\n" + table
+		}
+		tables = append(tables, table)
+		for _, item := range items {
+			records = append(records, util.ViewItemRecord{View: view.Query, ItemRecord: util.NewItemRecord(item, relations)})
+		}
+	}
+	return printList(func() string {
+		return strings.Join(tables, "\n")
+	}, util.ViewItemFields, records)
+}
+
 var filterAddCmd = &cobra.Command{
 	Use:   "add [name]",
 	Short: "add filter",
@@ -79,19 +161,15 @@ var filterAddCmd = &cobra.Command{
 			return err
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
-		filters := client.Filter.FindByName(name)
-		if len(filters) == 0 {
+		syncedFilter := client.Filter.Resolve(filter.ID)
+		if syncedFilter == nil {
 			return errors.New("Failed to add this filter. It may be failed to sync.")
 		}
-		syncedFilter := filters[len(filters)-1]
 		fmt.Println("succeeded to add a filter")
-		fmt.Println(util.FilterTableString([]todoist.Filter{syncedFilter}))
+		fmt.Println(util.FilterTableString([]todoist.Filter{*syncedFilter}))
 		return nil
 	},
 }
@@ -161,10 +239,7 @@ var filterUpdateCmd = &cobra.Command{
 			return err
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
 		syncedFilter := client.Filter.Resolve(id)
@@ -181,7 +256,7 @@ var filterDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "delete filter",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require filter id to delete")
 			}
@@ -214,6 +289,7 @@ var filterDeleteCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(filterCmd)
 	filterCmd.AddCommand(filterListCmd)
+	filterCmd.AddCommand(filterShowCmd)
 	filterAddCmd.Flags().StringP("query", "q", "", "query")
 	filterAddCmd.Flags().IntP("color", "c", 47, "color")
 	filterAddCmd.Flags().Int("order", 0, "item order")
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"errors"
 	"github.com/kobtea/go-todoist/cmd/util"
 	"github.com/kobtea/go-todoist/todoist"
@@ -23,13 +21,19 @@ var inboxCmd = &cobra.Command{
 			return errors.New("Failed to detect inbox. It may exist multiple inbox.")
 		}
 		inbox := projects[0]
+		expand, err := cmd.Flags().GetBool("expand")
+		if err != nil {
+			return err
+		}
 		items := client.Item.FindByProjectIDs([]todoist.ID{inbox.ID})
 		relations := client.Relation.Items(items)
-		fmt.Println(util.ItemTableString(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }))
-		return nil
+		return printList(func() string {
+			return util.ItemTableStringBySection(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }, expand)
+		}, util.ItemFields, util.NewItemRecords(util.SortItemsBySection(items, relations), relations))
 	},
 }
 
 func init() {
+	inboxCmd.Flags().BoolP("expand", "e", false, "show sub items of collapsed items")
 	RootCmd.AddCommand(inboxCmd)
 }
@@ -9,7 +9,6 @@ import (
 	"github.com/kobtea/go-todoist/todoist"
 	"github.com/spf13/cobra"
 	"os"
-	"sort"
 	"strings"
 	"time"
 )
@@ -28,13 +27,40 @@ var itemListCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if query, err := cmd.Flags().GetString("query"); err != nil {
+			return err
+		} else if len(query) != 0 {
+			views, err := client.Filter.Query(query)
+			if err != nil {
+				return err
+			}
+			return printFilterViews(client, views)
+		}
+		expand, err := cmd.Flags().GetBool("expand")
+		if err != nil {
+			return err
+		}
 		items := client.Item.GetAll()
 		relations := client.Relation.Items(items)
-		fmt.Println(util.ItemTableString(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }))
-		return nil
+		return printList(func() string {
+			return util.ItemTableStringBySection(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }, expand)
+		}, util.ItemFields, util.NewItemRecords(util.SortItemsBySection(items, relations), relations))
 	},
 }
 
+// parseDue parses the due date in the language of the user, so that the date is known before sync.
+// The server parses the string again, so the string is sent as is if it cannot be parsed locally.
+func parseDue(client *todoist.Client, value string) todoist.Due {
+	var lang string
+	if user := client.User.Get(); user != nil {
+		lang = user.Lang
+	}
+	if due, err := todoist.ParseDue(value, lang, client.Location()); err == nil {
+		return *due
+	}
+	return todoist.Due{String: value}
+}
+
 var itemAddCmd = &cobra.Command{
 	Use:   "add",
 	Short: "add items",
@@ -44,17 +70,54 @@ var itemAddCmd = &cobra.Command{
 			return err
 		}
 		content := strings.Join(args, " ")
-		opts := todoist.NewItemOpts{}
-		projectIDorName, err := cmd.Flags().GetString("project")
+		opts := &todoist.NewItemOpts{}
+		quick, err := cmd.Flags().GetBool("quick")
 		if err != nil {
-			return errors.New("invalid project id or name")
+			return err
 		}
-		if pid, err := todoist.NewID(projectIDorName); err != nil {
-			if project := client.Project.FindOneByName(projectIDorName); project != nil {
-				opts.ProjectID = project.ID
+		ctx := context.Background()
+		if quick && !changedAny(cmd, "project", "section", "label", "due", "priority") {
+			// the server parses the text like the official apps, and it is parsed locally while offline.
+			item, err := client.Item.QuickAdd(ctx, content)
+			if err == nil {
+				printAddedItem(client, *item)
+				return nil
+			}
+			if !todoist.IsTemporary(err) {
+				return err
 			}
-		} else {
-			opts.ProjectID = pid
+			fmt.Fprintf(os.Stderr, "failed to quick add on the server, parse it locally: %s\n", err)
+		}
+		if quick {
+			// the flags given explicitly take precedence over the quick add syntax.
+			if content, opts, err = client.Item.ParseQuickAdd(content); err != nil {
+				return err
+			}
+		}
+		projectIDorPath, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return errors.New("invalid project id or path")
+		}
+		if !quick || cmd.Flags().Changed("project") {
+			if pid, err := resolveProjectID(client, projectIDorPath); err != nil {
+				// the default project is the inbox, which is used when it is not found.
+				if cmd.Flags().Changed("project") {
+					return err
+				}
+			} else {
+				opts.ProjectID = pid
+			}
+		}
+		sectionIDorName, err := cmd.Flags().GetString("section")
+		if err != nil {
+			return errors.New("invalid section id or name")
+		}
+		if len(sectionIDorName) > 0 {
+			sid, err := resolveSectionID(client, opts.ProjectID, sectionIDorName)
+			if err != nil {
+				return err
+			}
+			opts.SectionID = sid
 		}
 		labelIDorNames, err := cmd.Flags().GetString("label")
 		if err != nil {
@@ -76,43 +139,51 @@ var itemAddCmd = &cobra.Command{
 			return errors.New("invalid due date format")
 		}
 		if len(due) > 0 {
-			opts.Due.String = due
+			opts.Due = parseDue(client, due)
 		}
 		priority, err := cmd.Flags().GetInt("priority")
 		if err != nil {
 			return errors.New("invalid priority")
 		}
-		opts.Priority = priority
-		item, err := todoist.NewItem(content, &opts)
+		if !quick || cmd.Flags().Changed("priority") {
+			opts.Priority = priority
+		}
+		item, err := todoist.NewItem(content, opts)
 		if err != nil {
 			return err
 		}
 		if _, err = client.Item.Add(*item); err != nil {
 			return err
 		}
-		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
-		// retrieve the item
-		items := client.Item.FindByContent(content)
-		if len(items) == 0 {
+		// retrieve the item by the temp id
+		syncedItem := client.Item.Resolve(item.ID)
+		if syncedItem == nil {
 			return errors.New("Failed to add this item. It may be failed to sync.")
 		}
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].DateAdded.Before(items[j].DateAdded)
-		})
-		syncedItem := items[len(items)-1]
-		relations := client.Relation.Items([]todoist.Item{syncedItem})
-		fmt.Println("Successful addition of an item.")
-		fmt.Println(util.ItemTableString([]todoist.Item{syncedItem}, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }))
+		printAddedItem(client, *syncedItem)
 		return nil
 	},
 }
 
+// changedAny reports whether any of the flags is given explicitly.
+func changedAny(cmd *cobra.Command, names ...string) bool {
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func printAddedItem(client *todoist.Client, item todoist.Item) {
+	relations := client.Relation.Items([]todoist.Item{item})
+	fmt.Println("Successful addition of an item.")
+	fmt.Println(util.ItemTableString([]todoist.Item{item}, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }))
+}
+
 var itemUpdateCmd = &cobra.Command{
 	Use:   "update id [new_content]",
 	Short: "update items",
@@ -157,7 +228,7 @@ var itemUpdateCmd = &cobra.Command{
 			return errors.New("invalid due date format")
 		}
 		if len(due) > 0 {
-			item.Due.String = due
+			item.Due = parseDue(client, due)
 		}
 
 		priority, err := cmd.Flags().GetInt("priority")
@@ -170,10 +241,7 @@ var itemUpdateCmd = &cobra.Command{
 			return err
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
 		syncedItem := client.Item.Resolve(id)
@@ -191,7 +259,7 @@ var itemDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "delete items",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) != 1 {
 				return fmt.Errorf("require one item id")
 			}
@@ -226,7 +294,7 @@ var itemDeleteCmd = &cobra.Command{
 
 var itemMoveCmd = &cobra.Command{
 	Use:   "move",
-	Short: "move the project of the item",
+	Short: "move the item to another parent, section or project",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := util.NewClient()
 		if err != nil {
@@ -245,16 +313,23 @@ var itemMoveCmd = &cobra.Command{
 		}
 
 		opts := &todoist.ItemMoveOpts{}
-		if parentID, err := cmd.Flags().GetString("parent"); err == nil {
+		if parentID, err := cmd.Flags().GetString("parent"); err == nil && len(parentID) != 0 {
 			if id, err := todoist.NewID(parentID); err != nil {
 				return fmt.Errorf("invalid parent id: %s", parentID)
 			} else {
 				opts.ParentID = id
 			}
 		}
-		if projectID, err := cmd.Flags().GetString("project"); err == nil {
-			if id, err  := todoist.NewID(projectID); err != nil {
-				return fmt.Errorf("invalid project id: %s", projectID)
+		if sectionIDorName, err := cmd.Flags().GetString("section"); err == nil && len(sectionIDorName) != 0 {
+			if id, err := resolveSectionID(client, item.ProjectID, sectionIDorName); err != nil {
+				return err
+			} else {
+				opts.SectionID = id
+			}
+		}
+		if projectIDorPath, err := cmd.Flags().GetString("project"); err == nil && len(projectIDorPath) != 0 {
+			if id, err := resolveProjectID(client, projectIDorPath); err != nil {
+				return err
 			} else {
 				opts.ProjectID = id
 			}
@@ -263,10 +338,7 @@ var itemMoveCmd = &cobra.Command{
 			return err
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
 		syncedItem := client.Item.Resolve(id)
@@ -280,11 +352,63 @@ var itemMoveCmd = &cobra.Command{
 	},
 }
 
+var itemAssignCmd = &cobra.Command{
+	Use:   "assign [id] [email]",
+	Short: "assign the item to a collaborator of the project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		if len(args) != 2 {
+			return errors.New("require item id and email to assign")
+		}
+		id, err := todoist.NewID(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid id: %s", args[0])
+		}
+		item := client.Item.Resolve(id)
+		if item == nil {
+			return fmt.Errorf("no such item id: %s", id)
+		}
+		email := args[1]
+		var responsible *todoist.Collaborator
+		if user := client.User.Get(); user != nil && user.Email == email {
+			responsible = client.Collaborator.ResolveUser(user.ID)
+		}
+		for _, collaborator := range client.Collaborator.FindByProjectID(item.ProjectID) {
+			if collaborator.Email == email {
+				responsible = &collaborator
+				break
+			}
+		}
+		if responsible == nil {
+			return fmt.Errorf("%s is not a collaborator of the project", email)
+		}
+		item.ResponsibleUID = responsible.ID
+		if _, err = client.Item.Update(*item); err != nil {
+			return err
+		}
+		ctx := context.Background()
+		if err = util.Commit(client, ctx); err != nil {
+			return err
+		}
+		syncedItem := client.Item.Resolve(id)
+		if syncedItem == nil {
+			return errors.New("failed to assign this item. it may be failed to sync")
+		}
+		relations := client.Relation.Items([]todoist.Item{*syncedItem})
+		fmt.Println("succeeded to assign the item")
+		fmt.Println(util.ItemTableString([]todoist.Item{*syncedItem}, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }))
+		return nil
+	},
+}
+
 var itemCompleteCmd = &cobra.Command{
 	Use:   "complete",
 	Short: "complete items",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) != 1 {
 				return fmt.Errorf("require one item id")
 			}
@@ -292,8 +416,31 @@ var itemCompleteCmd = &cobra.Command{
 			if err != nil {
 				return err
 			}
+			// a recurring item is moved to the next occurrence, and its sub items are not completed.
+			if item := client.Item.Resolve(id); item != nil && item.Due.IsRecurring {
+				// the item is closed even if the recurrence cannot be parsed locally, then the next occurrence is not printed.
+				next, nextErr := item.Due.Next(todoist.Time{Time: time.Now().UTC()}, client.Location())
+				if err = client.Item.Close(id); err != nil {
+					return err
+				}
+				if nextErr == nil {
+					fmt.Printf("the next occurrence is %s\n", next.Date.In(client.Location()))
+				}
+				return nil
+			}
+			var subItems []todoist.Item
+			for _, i := range client.Item.Subtree(id) {
+				if !i.IsChecked() {
+					subItems = append(subItems, i)
+				}
+			}
+			if len(subItems) != 0 {
+				relations := client.Relation.Items(subItems)
+				fmt.Println("the following sub items are completed as well")
+				fmt.Println(util.ItemTreeTableString(subItems, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }, true))
+			}
 			// FIXME: support date_completed option
-			date := todoist.Time{time.Now().UTC()}
+			date := todoist.Time{Time: time.Now().UTC()}
 			return client.Item.Complete(id, date, true)
 		}); err != nil {
 			return err
@@ -307,7 +454,7 @@ var itemUncompleteCmd = &cobra.Command{
 	Use:   "uncomplete",
 	Short: "uncomplete items",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) != 1 {
 				return fmt.Errorf("require one item id")
 			}
@@ -326,13 +473,17 @@ var itemUncompleteCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(itemCmd)
+	itemListCmd.Flags().BoolP("expand", "e", false, "show sub items of collapsed items")
+	itemListCmd.Flags().StringP("query", "q", "", "filter query, e.g. \"(today | overdue) & #Work\"")
 	itemCmd.AddCommand(itemListCmd)
-	itemAddCmd.Flags().StringP("project", "p", "inbox", "project id or name")
+	itemAddCmd.Flags().StringP("project", "p", "inbox", "project id or path")
 	itemAddCmd.Flag("project").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_project_id"}}
+	itemAddCmd.Flags().StringP("section", "s", "", "section id or name in the project")
 	itemAddCmd.Flags().StringP("label", "l", "", "label id or name(s) (delimiter: ,)")
 	itemAddCmd.Flag("label").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_label_id"}}
 	itemAddCmd.Flags().StringP("due", "d", "", "due date")
 	itemAddCmd.Flags().Int("priority", 1, "priority")
+	itemAddCmd.Flags().BoolP("quick", "q", false, "parse the content like \"Pay rent #Home @finance p1 every 1st\"")
 	itemCmd.AddCommand(itemAddCmd)
 	itemUpdateCmd.Flags().StringP("label", "l", "", "label id(s) or name(s) (delimiter: ,)")
 	itemUpdateCmd.Flag("label").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_label_id"}}
@@ -342,9 +493,12 @@ func init() {
 	itemCmd.AddCommand(itemDeleteCmd)
 	itemMoveCmd.Flags().StringP("parent", "i", "", "parent item id")
 	itemMoveCmd.Flag("parent").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_item_id"}}
-	itemMoveCmd.Flags().StringP("project", "p", "", "project id")
+	itemMoveCmd.Flags().StringP("section", "s", "", "section id or name in the project of the item")
+	itemMoveCmd.Flag("section").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_section_id"}}
+	itemMoveCmd.Flags().StringP("project", "p", "", "project id or path")
 	itemMoveCmd.Flag("project").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_project_id"}}
 	itemCmd.AddCommand(itemMoveCmd)
+	itemCmd.AddCommand(itemAssignCmd)
 	itemCmd.AddCommand(itemCompleteCmd)
 	itemCmd.AddCommand(itemUncompleteCmd)
 }
@@ -27,8 +27,9 @@ var labelListCmd = &cobra.Command{
 			return err
 		}
 		labels := client.Label.GetAll()
-		fmt.Println(util.LabelTableString(labels))
-		return nil
+		return printList(func() string {
+			return util.LabelTableString(labels)
+		}, util.LabelFields, util.NewLabelRecords(labels))
 	},
 }
 
@@ -67,20 +68,15 @@ var labelAddCmd = &cobra.Command{
 			return err
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
-		labels := client.Label.FindByName(name)
-		if len(labels) == 0 {
+		syncedLabel := client.Label.Resolve(label.ID)
+		if syncedLabel == nil {
 			return errors.New("failed to add this label. it may be failed to sync")
 		}
-		// it may not be new label
-		syncedLabel := labels[len(labels)-1]
 		fmt.Println("succeeded to add a label")
-		fmt.Println(util.LabelTableString([]todoist.Label{syncedLabel}))
+		fmt.Println(util.LabelTableString([]todoist.Label{*syncedLabel}))
 		return nil
 	},
 }
@@ -143,10 +139,7 @@ var labelUpdateCmd = &cobra.Command{
 			return err
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
 		syncedLabel := client.Label.Resolve(id)
@@ -163,7 +156,7 @@ var labelDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "delete label",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require label id to delete")
 			}
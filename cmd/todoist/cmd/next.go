@@ -1,11 +1,11 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/kobtea/go-todoist/cmd/util"
 	"github.com/kobtea/go-todoist/todoist"
 	"github.com/spf13/cobra"
 	"sort"
+	"time"
 )
 
 // nextCmd represents the next command
@@ -17,18 +17,38 @@ var nextCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		loc := client.Location()
+		start, end := todoist.Time{Time: todoist.TodayIn(loc).Add(-24 * time.Hour)}, todoist.Next7DaysIn(loc)
 		var items []todoist.Item
-		for _, i := range client.Item.FindByDueDate(todoist.Next7Days()) {
-			if !i.IsChecked() {
-				items = append(items, i)
+		for _, i := range client.Item.FindByDueDate(end) {
+			if i.IsChecked() {
+				continue
+			}
+			items = append(items, i)
+			// show the upcoming occurrences of recurring items as well.
+			if !i.Due.IsRecurring {
+				continue
+			}
+			r, err := i.Due.Recurrence()
+			if err != nil {
+				continue
+			}
+			for next := r.Next(i.Due.Date, loc); !next.IsZero() && next.Before(end); next = r.Next(next, loc) {
+				if next.Before(start) {
+					continue
+				}
+				occurrence := i
+				occurrence.Due.Date = next
+				items = append(items, occurrence)
 			}
 		}
 		sort.Slice(items, func(i, j int) bool {
 			return items[i].Due.Date.Before(items[j].Due.Date)
 		})
 		relations := client.Relation.Items(items)
-		fmt.Println(util.ItemTableString(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }))
-		return nil
+		return printList(func() string {
+			return util.ItemTableString(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date })
+		}, util.ItemFields, util.NewItemRecords(items, relations))
 	},
 }
 
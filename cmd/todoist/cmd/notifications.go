@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kobtea/go-todoist/cmd/util"
+	"github.com/kobtea/go-todoist/todoist"
+	"github.com/spf13/cobra"
+)
+
+// notificationsCmd represents the notifications command
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "show unread notifications",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		notifications := client.Notification.GetUnread()
+		if all, err := cmd.Flags().GetBool("all"); err != nil {
+			return err
+		} else if all {
+			notifications = client.Notification.GetAll()
+		}
+		relations := client.Relation.LiveNotifications(notifications)
+		return printList(func() string {
+			return util.NotificationTableString(notifications, relations)
+		}, util.NotificationFields, util.NewNotificationRecords(notifications, relations))
+	},
+}
+
+var notificationsReadCmd = &cobra.Command{
+	Use:   "read [id...]",
+	Short: "mark notifications as read",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			if all {
+				return client.Notification.MarkAllRead()
+			}
+			if len(args) == 0 {
+				return errors.New("require notification id(s) or --all")
+			}
+			return util.ProcessIDs(args, func(ids []todoist.ID) error {
+				for _, id := range ids {
+					if err := client.Notification.MarkRead(id); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to mark the notification(s) as read")
+		return nil
+	},
+}
+
+var notificationsUnreadCmd = &cobra.Command{
+	Use:   "unread [id...]",
+	Short: "mark notifications as unread",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			return util.ProcessIDs(args, func(ids []todoist.ID) error {
+				for _, id := range ids {
+					if err := client.Notification.MarkUnread(id); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to mark the notification(s) as unread")
+		return nil
+	},
+}
+
+var notificationsAcceptCmd = &cobra.Command{
+	Use:   "accept [id]",
+	Short: "accept the invitation to share a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			invitation, err := resolveInvitation(client, args)
+			if err != nil {
+				return err
+			}
+			return client.Collaborator.AcceptInvitation(invitation.InvitationID, invitation.InvitationSecret)
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to accept the invitation")
+		return nil
+	},
+}
+
+var notificationsRejectCmd = &cobra.Command{
+	Use:   "reject [id]",
+	Short: "reject the invitation to share a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			invitation, err := resolveInvitation(client, args)
+			if err != nil {
+				return err
+			}
+			return client.Collaborator.RejectInvitation(invitation.InvitationID, invitation.InvitationSecret)
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to reject the invitation")
+		return nil
+	},
+}
+
+// resolveInvitation resolves the notification of an invitation, which has the id and the secret of it.
+func resolveInvitation(client *todoist.Client, args []string) (*todoist.LiveNotification, error) {
+	if len(args) != 1 {
+		return nil, errors.New("require one notification id")
+	}
+	id, err := todoist.NewID(args[0])
+	if err != nil {
+		return nil, err
+	}
+	n := client.Notification.Resolve(id)
+	if n == nil {
+		return nil, fmt.Errorf("invalid notification id: %s", id)
+	}
+	if n.InvitationID.IsZero() || len(n.InvitationSecret) == 0 {
+		return nil, fmt.Errorf("not an invitation: %s", id)
+	}
+	return n, nil
+}
+
+func init() {
+	RootCmd.AddCommand(notificationsCmd)
+	notificationsCmd.Flags().BoolP("all", "a", false, "show read notifications as well")
+	notificationsReadCmd.Flags().BoolP("all", "a", false, "mark all the notifications as read")
+	notificationsCmd.AddCommand(notificationsReadCmd)
+	notificationsCmd.AddCommand(notificationsUnreadCmd)
+	notificationsCmd.AddCommand(notificationsAcceptCmd)
+	notificationsCmd.AddCommand(notificationsRejectCmd)
+}
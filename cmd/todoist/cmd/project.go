@@ -26,9 +26,30 @@ var projectListCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		projects := client.Project.GetAll()
-		fmt.Println(util.ProjectTableString(projects))
-		return nil
+		archived, err := cmd.Flags().GetBool("archived")
+		if err != nil {
+			return err
+		}
+		expand, err := cmd.Flags().GetBool("expand")
+		if err != nil {
+			return err
+		}
+		withSections, err := cmd.Flags().GetBool("sections")
+		if err != nil {
+			return err
+		}
+		var projects []todoist.Project
+		for _, p := range client.Project.GetAll() {
+			if archived || !p.IsArchived.Bool() {
+				projects = append(projects, p)
+			}
+		}
+		return printList(func() string {
+			if !withSections {
+				return util.ProjectTreeTableString(projects, expand)
+			}
+			return util.ProjectSectionTableString(projects, client.Section.FindByProjectID, expand)
+		}, util.ProjectFields, util.NewProjectRecords(projects, client.Project.PathString, client.Section.FindByProjectID))
 	},
 }
 
@@ -54,7 +75,7 @@ var projectAddCmd = &cobra.Command{
 			return err
 		} else {
 			if len(parentStr) != 0 {
-				if parent, err := todoist.NewID(parentStr); err != nil {
+				if parent, err := resolveProjectID(client, parentStr); err != nil {
 					return err
 				} else {
 					opts.ParentID = parent
@@ -82,20 +103,15 @@ var projectAddCmd = &cobra.Command{
 			return err
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
-		projects := client.Project.FindByName(name)
-		if len(projects) == 0 {
+		syncedProject := client.Project.Resolve(project.ID)
+		if syncedProject == nil {
 			return errors.New("failed to add this project. it may be failed to sync")
 		}
-		// it may not be new project
-		syncedProject := projects[len(projects)-1]
 		fmt.Println("succeeded to add a project")
-		fmt.Println(util.ProjectTableString([]todoist.Project{syncedProject}))
+		fmt.Println(util.ProjectTableString([]todoist.Project{*syncedProject}))
 		return nil
 	},
 }
@@ -168,7 +184,7 @@ var projectUpdateCmd = &cobra.Command{
 			return err
 		} else {
 			if cmd.Flags().Changed("parent") {
-				if parent, err := todoist.NewID(parentStr); err != nil {
+				if parent, err := resolveProjectID(client, parentStr); err != nil {
 					return err
 				} else {
 					if err = client.Project.Move(project.ID, parent); err != nil {
@@ -188,10 +204,7 @@ var projectUpdateCmd = &cobra.Command{
 			}
 		}
 		ctx := context.Background()
-		if err = client.Commit(ctx); err != nil {
-			return err
-		}
-		if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+		if err = util.Commit(client, ctx); err != nil {
 			return err
 		}
 		syncedProject := client.Project.Resolve(id)
@@ -208,7 +221,7 @@ var projectDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "delete project",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require project id to delete")
 			}
@@ -242,7 +255,7 @@ var projectArchiveCmd = &cobra.Command{
 	Use:   "archive [id]",
 	Short: "archive project",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require project id to archive")
 			}
@@ -261,7 +274,7 @@ var projectUnarchiveCmd = &cobra.Command{
 	Use:   "unarchive [id]",
 	Short: "unarchive project",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require project id to un-archive")
 			}
@@ -276,18 +289,109 @@ var projectUnarchiveCmd = &cobra.Command{
 	},
 }
 
+var projectShareCmd = &cobra.Command{
+	Use:   "share [id] [email...]",
+	Short: "share project with users",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			if len(args) < 2 {
+				return errors.New("require project id and email(s) to share")
+			}
+			return util.ProcessID(args[0], func(id todoist.ID) error {
+				if client.Project.Resolve(id) == nil {
+					return fmt.Errorf("invalid project id: %s", id)
+				}
+				for _, email := range args[1:] {
+					if err := client.Project.Share(id, email); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to share the project")
+		return nil
+	},
+}
+
+var projectUnshareCmd = &cobra.Command{
+	Use:   "unshare [id] [email...]",
+	Short: "remove collaborators from project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			if len(args) < 2 {
+				return errors.New("require project id and email(s) to unshare")
+			}
+			return util.ProcessID(args[0], func(id todoist.ID) error {
+				if client.Project.Resolve(id) == nil {
+					return fmt.Errorf("invalid project id: %s", id)
+				}
+				// check all the emails first, not to remove a part of them.
+				for _, email := range args[1:] {
+					if !isCollaborator(client, id, email) {
+						return fmt.Errorf("not a collaborator of the project: %s", email)
+					}
+				}
+				for _, email := range args[1:] {
+					if err := client.Collaborator.Delete(id, email); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to remove the collaborator(s)")
+		return nil
+	},
+}
+
+// resolveProjectID resolves the project by the id or the path like "#Work/Backend".
+// If no project matches a single name, the projects partially matching it are listed as candidates.
+func resolveProjectID(client *todoist.Client, idOrPath string) (todoist.ID, error) {
+	if id, err := todoist.NewID(idOrPath); err == nil {
+		return id, nil
+	}
+	project, err := client.Project.ResolvePath(idOrPath)
+	if err == nil {
+		return project.ID, nil
+	}
+	if perr, ok := err.(*todoist.ProjectPathError); ok && len(perr.Candidates) == 0 && !strings.Contains(idOrPath, "/") {
+		for _, p := range client.Project.FindByName(idOrPath) {
+			perr.Candidates = append(perr.Candidates, client.Project.PathString(p.ID))
+		}
+	}
+	return "", err
+}
+
+// isCollaborator reports whether the user of the email is active or invited in the project.
+func isCollaborator(client *todoist.Client, projectID todoist.ID, email string) bool {
+	for _, state := range client.Collaborator.GetStates(projectID) {
+		if c := client.Collaborator.Resolve(state.UserID); c != nil && c.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	RootCmd.AddCommand(projectCmd)
+	projectListCmd.Flags().BoolP("archived", "a", false, "show archived projects as well")
+	projectListCmd.Flags().BoolP("expand", "e", false, "show sub projects of collapsed projects")
+	projectListCmd.Flags().BoolP("sections", "s", false, "show sections under each project")
 	projectCmd.AddCommand(projectListCmd)
 	projectAddCmd.Flags().IntP("color", "c", 47, "color")
-	projectAddCmd.Flags().String("parent", "", "parent project id")
+	projectAddCmd.Flags().String("parent", "", "parent project id or path")
 	projectAddCmd.Flag("parent").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_project_id"}}
 	projectAddCmd.Flags().Int("order", 0, "child order")
 	projectAddCmd.Flags().Bool("favorite", false, "is favorite")
 	projectCmd.AddCommand(projectAddCmd)
 	projectUpdateCmd.Flags().String("name", "", "name of the project")
 	projectUpdateCmd.Flags().IntP("color", "c", 47, "color")
-	projectUpdateCmd.Flags().String("parent", "", "parent project id")
+	projectUpdateCmd.Flags().String("parent", "", "parent project id or path")
 	projectUpdateCmd.Flag("parent").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_project_id"}}
 	projectUpdateCmd.Flags().Int("order", 0, "child order")
 	projectUpdateCmd.Flags().Bool("collapsed", false, "collapse project")
@@ -298,4 +402,6 @@ func init() {
 	projectCmd.AddCommand(projectDeleteCmd)
 	projectCmd.AddCommand(projectArchiveCmd)
 	projectCmd.AddCommand(projectUnarchiveCmd)
+	projectCmd.AddCommand(projectShareCmd)
+	projectCmd.AddCommand(projectUnshareCmd)
 }
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kobtea/go-todoist/cmd/util"
+	"github.com/kobtea/go-todoist/todoist"
+	"github.com/spf13/cobra"
+)
+
+// queueCmd represents the queue command
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "subcommand for commands queued while offline",
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list queued commands",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		queue := client.Queue()
+		return printList(func() string {
+			return util.CommandTableString(queue)
+		}, util.CommandFields, util.NewCommandRecords(queue))
+	},
+}
+
+var queueDropCmd = &cobra.Command{
+	Use:   "drop [uuid...]",
+	Short: "drop queued commands",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("require command uuid(s) to drop")
+		}
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		for _, uuid := range args {
+			if err = client.DropCommand(todoist.UUID(uuid)); err != nil {
+				return err
+			}
+		}
+		fmt.Println("succeeded to drop the command(s)")
+		return nil
+	},
+}
+
+var queueReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "send queued commands",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		n := len(client.Queue())
+		if n == 0 {
+			fmt.Println("no queued commands")
+			return nil
+		}
+		if err = client.Commit(context.Background()); err != nil {
+			return err
+		}
+		fmt.Printf("succeeded to send %d command(s)\n", n)
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueListCmd)
+	queueCmd.AddCommand(queueDropCmd)
+	queueCmd.AddCommand(queueReplayCmd)
+}
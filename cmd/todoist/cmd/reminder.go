@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kobtea/go-todoist/cmd/util"
+	"github.com/kobtea/go-todoist/todoist"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+// reminderCmd represents the reminder command
+var reminderCmd = &cobra.Command{
+	Use:   "reminder",
+	Short: "subcommand for reminder",
+}
+
+var reminderListCmd = &cobra.Command{
+	Use:   "list [item_id...]",
+	Short: "list reminders",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		printReminders := func(reminders []todoist.Reminder) error {
+			return printList(func() string {
+				return util.ReminderTableString(reminders, client.Item.Resolve, client.Location())
+			}, util.ReminderFields, util.NewReminderRecords(reminders, client.Item.Resolve, client.Location()))
+		}
+		if len(args) == 0 {
+			return printReminders(client.Reminder.GetAll())
+		}
+		return util.ProcessIDs(args, func(ids []todoist.ID) error {
+			var reminders []todoist.Reminder
+			for _, id := range ids {
+				reminders = append(reminders, client.Reminder.GetAllForItem(id)...)
+			}
+			return printReminders(reminders)
+		})
+	},
+}
+
+var reminderAddCmd = &cobra.Command{
+	Use:   "add [item_id...]",
+	Short: "add reminders to items",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		offset, err := cmd.Flags().GetInt("offset")
+		if err != nil {
+			return err
+		}
+		date, err := cmd.Flags().GetString("date")
+		if err != nil {
+			return err
+		}
+		location, err := cmd.Flags().GetString("location")
+		if err != nil {
+			return err
+		}
+		lat, err := cmd.Flags().GetString("lat")
+		if err != nil {
+			return err
+		}
+		long, err := cmd.Flags().GetString("long")
+		if err != nil {
+			return err
+		}
+		trigger, err := cmd.Flags().GetString("trigger")
+		if err != nil {
+			return err
+		}
+		radius, err := cmd.Flags().GetInt("radius")
+		if err != nil {
+			return err
+		}
+		n := 0
+		for _, name := range []string{"offset", "date", "location"} {
+			if cmd.Flags().Changed(name) {
+				n++
+			}
+		}
+		if n != 1 {
+			return errors.New("require one of --offset, --date or --location")
+		}
+		var due todoist.Due
+		if len(date) != 0 {
+			t, err := todoist.ParseIn(date, client.Location())
+			if err != nil {
+				return fmt.Errorf("invalid date: %s", date)
+			}
+			due.Date = t
+		}
+		var reminders []todoist.Reminder
+		if err = util.ProcessIDs(args, func(ids []todoist.ID) error {
+			for _, id := range ids {
+				if client.Item.Resolve(id) == nil {
+					return fmt.Errorf("invalid item id: %s", id)
+				}
+				opts := todoist.NewReminderOpts{}
+				var reminder *todoist.Reminder
+				var err error
+				switch {
+				case cmd.Flags().Changed("offset"):
+					reminder, err = todoist.NewRelativeReminder(id, offset, &opts)
+				case cmd.Flags().Changed("date"):
+					reminder, err = todoist.NewAbsoluteReminder(id, due, &opts)
+				default:
+					reminder, err = todoist.NewLocationReminder(id, location, lat, long, trigger, radius, &opts)
+				}
+				if err != nil {
+					return err
+				}
+				if _, err = client.Reminder.Add(*reminder); err != nil {
+					return err
+				}
+				reminders = append(reminders, *reminder)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		ctx := context.Background()
+		if err = util.Commit(client, ctx); err != nil {
+			return err
+		}
+		var synced []todoist.Reminder
+		for _, r := range reminders {
+			if reminder := client.Reminder.Resolve(r.ID); reminder != nil {
+				synced = append(synced, *reminder)
+			}
+		}
+		if len(synced) != len(reminders) {
+			return errors.New("failed to add reminder(s). it may be failed to sync")
+		}
+		fmt.Println("succeeded to add reminder(s)")
+		fmt.Println(util.ReminderTableString(synced, client.Item.Resolve, client.Location()))
+		return nil
+	},
+}
+
+var reminderDeleteCmd = &cobra.Command{
+	Use:   "delete [id...]",
+	Short: "delete reminders",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			return util.ProcessIDs(args, func(ids []todoist.ID) error {
+				var reminders []todoist.Reminder
+				for _, id := range ids {
+					reminder := client.Reminder.Resolve(id)
+					if reminder == nil {
+						return fmt.Errorf("invalid reminder id: %s", id)
+					}
+					reminders = append(reminders, *reminder)
+				}
+				fmt.Println(util.ReminderTableString(reminders, client.Item.Resolve, client.Location()))
+				reader := bufio.NewReader(os.Stdin)
+				fmt.Print("are you sure to delete above reminder(s)? (y/[n]): ")
+				ans, err := reader.ReadString('\n')
+				if ans != "y\n" || err != nil {
+					fmt.Println("abort")
+					return errors.New("abort")
+				}
+				for _, id := range ids {
+					if err = client.Reminder.Delete(id); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}); err != nil {
+			if err.Error() == "abort" {
+				return nil
+			}
+			return err
+		}
+		fmt.Println("succeeded to delete reminder(s)")
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(reminderCmd)
+	reminderCmd.AddCommand(reminderListCmd)
+	reminderAddCmd.Flags().IntP("offset", "o", 30, "minutes before the due date")
+	reminderAddCmd.Flags().StringP("date", "d", "", "date to remind, e.g. 2006-01-02T15:04:05")
+	reminderAddCmd.Flags().String("location", "", "name of the location")
+	reminderAddCmd.Flags().String("lat", "", "latitude of the location")
+	reminderAddCmd.Flags().String("long", "", "longitude of the location")
+	reminderAddCmd.Flags().String("trigger", "on_enter", "on_enter or on_leave the location")
+	reminderAddCmd.Flags().Int("radius", 100, "radius of the location in meters")
+	reminderCmd.AddCommand(reminderAddCmd)
+	reminderCmd.AddCommand(reminderDeleteCmd)
+}
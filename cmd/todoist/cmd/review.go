@@ -6,6 +6,7 @@ import (
 	"github.com/kobtea/go-todoist/cmd/util"
 	"github.com/kobtea/go-todoist/todoist"
 	"github.com/spf13/cobra"
+	"os"
 	"sort"
 )
 
@@ -19,7 +20,7 @@ var reviewCmd = &cobra.Command{
 			return errors.New("Invalid sort key")
 		}
 		if sortKey != "time" && sortKey != "project" {
-			fmt.Println("Invalid sort key was given. Use default key (time).")
+			fmt.Fprintln(os.Stderr, "Invalid sort key was given. Use default key (time).")
 		}
 
 		client, err := util.NewClient()
@@ -43,8 +44,9 @@ var reviewCmd = &cobra.Command{
 			}
 		})
 		relations := client.Relation.Items(completed.Items)
-		fmt.Println(util.ItemTableString(completed.Items, relations, func(i todoist.Item) todoist.Time { return i.CompletedDate }))
-		return nil
+		return printList(func() string {
+			return util.ItemTableString(completed.Items, relations, func(i todoist.Item) todoist.Time { return i.CompletedDate })
+		}, util.ItemFields, util.NewItemRecords(completed.Items, relations))
 	},
 }
 
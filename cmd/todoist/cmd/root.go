@@ -3,13 +3,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/kobtea/go-todoist/cmd/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
 
+// outputFormat is the format of the listings, one of util.OutputFormats.
+var outputFormat string
+
 var RootCmd = &cobra.Command{
 	Use:   "todoist",
 	Short: "Command line tool for todoist.",
@@ -32,6 +37,16 @@ func init() {
 	// Cobra supports Persistent Flags, which, if defined here,
 	// will be global for your application.
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.todoist.yaml)")
+	RootCmd.PersistentFlags().StringVar(&outputFormat, "output", "table", "output format of listings ("+strings.Join(util.OutputFormats, ", ")+")")
+}
+
+// printList prints the table if the output format is table, or the records in the format.
+func printList(table func() string, header []string, records []util.Record) error {
+	if outputFormat == "table" {
+		fmt.Println(table())
+		return nil
+	}
+	return util.WriteRecords(os.Stdout, outputFormat, header, records)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -46,6 +61,7 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		// not to mix with the structured output.
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kobtea/go-todoist/cmd/util"
+	"github.com/kobtea/go-todoist/todoist"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+// sectionCmd represents the section command
+var sectionCmd = &cobra.Command{
+	Use:   "section",
+	Short: "subcommand for section",
+}
+
+// resolveSectionID resolves the section by the id, or by the name in the project if it is given.
+func resolveSectionID(client *todoist.Client, projectID todoist.ID, idOrName string) (todoist.ID, error) {
+	if id, err := todoist.NewID(idOrName); err == nil {
+		return id, nil
+	}
+	sections := client.Section.GetAll()
+	if !projectID.IsZero() {
+		sections = client.Section.FindByProjectID(projectID)
+	}
+	name := strings.TrimPrefix(idOrName, "/")
+	for _, section := range sections {
+		if section.Name == name {
+			return section.ID, nil
+		}
+	}
+	for _, section := range sections {
+		if strings.Contains(section.Name, name) {
+			return section.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no such section: %s", idOrName)
+}
+
+var sectionListCmd = &cobra.Command{
+	Use:   "list [project_id|name]",
+	Short: "list sections",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		sections := client.Section.GetAll()
+		if len(args) != 0 {
+			pid, err := resolveProjectID(client, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			sections = client.Section.FindByProjectID(pid)
+		}
+		return printList(func() string {
+			return util.SectionTableString(sections, client.Project.Resolve)
+		}, util.SectionFields, util.NewSectionRecords(sections, client.Project.Resolve))
+	},
+}
+
+var sectionAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "add a section",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		name := strings.Join(args, " ")
+		if len(name) == 0 {
+			return errors.New("require section name")
+		}
+		projectIDorName, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+		if len(projectIDorName) == 0 {
+			return errors.New("require project id or name")
+		}
+		pid, err := resolveProjectID(client, projectIDorName)
+		if err != nil {
+			return err
+		}
+		opts := todoist.NewSectionOpts{}
+		if order, err := cmd.Flags().GetInt("order"); err != nil {
+			return err
+		} else {
+			opts.SectionOrder = order
+		}
+		section, err := todoist.NewSection(name, pid, &opts)
+		if err != nil {
+			return err
+		}
+		if _, err = client.Section.Add(*section); err != nil {
+			return err
+		}
+		ctx := context.Background()
+		if err = util.Commit(client, ctx); err != nil {
+			return err
+		}
+		syncedSection := client.Section.Resolve(section.ID)
+		if syncedSection == nil {
+			return errors.New("failed to add this section. it may be failed to sync")
+		}
+		fmt.Println("succeeded to add a section")
+		fmt.Println(util.SectionTableString([]todoist.Section{*syncedSection}, client.Project.Resolve))
+		return nil
+	},
+}
+
+var sectionUpdateCmd = &cobra.Command{
+	Use:   "update [id]",
+	Short: "update section",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := util.NewClient()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return errors.New("require section id to update")
+		}
+		id, err := todoist.NewID(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid id: %s", args[0])
+		}
+		section := client.Section.Resolve(id)
+		if section == nil {
+			return fmt.Errorf("no such section id: %s", id)
+		}
+		if name, err := cmd.Flags().GetString("name"); err != nil {
+			return err
+		} else {
+			if len(name) != 0 {
+				section.Name = name
+			}
+		}
+		if collapsed, err := cmd.Flags().GetBool("collapsed"); err != nil {
+			return err
+		} else {
+			if collapsed {
+				section.Collapsed = true
+			}
+		}
+		if unCollapsed, err := cmd.Flags().GetBool("un-collapsed"); err != nil {
+			return err
+		} else {
+			if unCollapsed {
+				section.Collapsed = false
+			}
+		}
+		if _, err = client.Section.Update(*section); err != nil {
+			return err
+		}
+		if order, err := cmd.Flags().GetInt("order"); err != nil {
+			return err
+		} else {
+			if cmd.Flags().Changed("order") {
+				section.SectionOrder = order
+				if err = client.Section.Reorder([]todoist.Section{*section}); err != nil {
+					return err
+				}
+			}
+		}
+		ctx := context.Background()
+		if err = util.Commit(client, ctx); err != nil {
+			return err
+		}
+		syncedSection := client.Section.Resolve(id)
+		if syncedSection == nil {
+			return errors.New("failed to update this section. it may be failed to sync")
+		}
+		fmt.Println("succeeded to update the section")
+		fmt.Println(util.SectionTableString([]todoist.Section{*syncedSection}, client.Project.Resolve))
+		return nil
+	},
+}
+
+var sectionMoveCmd = &cobra.Command{
+	Use:   "move [id]",
+	Short: "move section to another project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			if len(args) == 0 {
+				return errors.New("require section id to move")
+			}
+			projectIDorName, err := cmd.Flags().GetString("project")
+			if err != nil {
+				return err
+			}
+			if len(projectIDorName) == 0 {
+				return errors.New("require project id or name")
+			}
+			pid, err := resolveProjectID(client, projectIDorName)
+			if err != nil {
+				return err
+			}
+			return util.ProcessID(args[0], func(id todoist.ID) error {
+				return client.Section.Move(id, pid)
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to move the section")
+		return nil
+	},
+}
+
+var sectionReorderCmd = &cobra.Command{
+	Use:   "reorder [id...]",
+	Short: "reorder sections in the given order",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			return util.ProcessIDs(args, func(ids []todoist.ID) error {
+				var sections []todoist.Section
+				for i, id := range ids {
+					section := client.Section.Resolve(id)
+					if section == nil {
+						return fmt.Errorf("invalid section id: %s", id)
+					}
+					section.SectionOrder = i + 1
+					sections = append(sections, *section)
+				}
+				return client.Section.Reorder(sections)
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to reorder the sections")
+		return nil
+	},
+}
+
+var sectionArchiveCmd = &cobra.Command{
+	Use:   "archive [id]",
+	Short: "archive section",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			if len(args) == 0 {
+				return errors.New("require section id to archive")
+			}
+			return util.ProcessID(args[0], func(id todoist.ID) error {
+				return client.Section.Archive(id)
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to archive the section")
+		return nil
+	},
+}
+
+var sectionUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive [id]",
+	Short: "unarchive section",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			if len(args) == 0 {
+				return errors.New("require section id to un-archive")
+			}
+			return util.ProcessID(args[0], func(id todoist.ID) error {
+				return client.Section.Unarchive(id)
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to un-archive the section")
+		return nil
+	},
+}
+
+var sectionDeleteCmd = &cobra.Command{
+	Use:   "delete [id]",
+	Short: "delete section",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			if len(args) == 0 {
+				return errors.New("require section id to delete")
+			}
+			return util.ProcessID(args[0], func(id todoist.ID) error {
+				section := client.Section.Resolve(id)
+				if section == nil {
+					return fmt.Errorf("invalid section id: %s", id)
+				}
+				fmt.Println(util.SectionTableString([]todoist.Section{*section}, client.Project.Resolve))
+				reader := bufio.NewReader(os.Stdin)
+				fmt.Print("are you sure to delete above section and its items? (y/[n]): ")
+				ans, err := reader.ReadString('\n')
+				if ans != "y\n" || err != nil {
+					fmt.Println("abort")
+					return errors.New("abort")
+				}
+				return client.Section.Delete(id)
+			})
+		}); err != nil {
+			if err.Error() == "abort" {
+				return nil
+			}
+			return err
+		}
+		fmt.Println("succeeded to delete the section")
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(sectionCmd)
+	sectionCmd.AddCommand(sectionListCmd)
+	sectionAddCmd.Flags().StringP("project", "p", "", "project id or path")
+	sectionAddCmd.Flag("project").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_project_id"}}
+	sectionAddCmd.Flags().Int("order", 0, "section order")
+	sectionCmd.AddCommand(sectionAddCmd)
+	sectionUpdateCmd.Flags().String("name", "", "name of the section")
+	sectionUpdateCmd.Flags().Bool("collapsed", false, "collapse the section")
+	sectionUpdateCmd.Flags().Bool("un-collapsed", false, "un-collapse the section")
+	sectionUpdateCmd.Flags().Int("order", 0, "section order")
+	sectionCmd.AddCommand(sectionUpdateCmd)
+	sectionMoveCmd.Flags().StringP("project", "p", "", "project id or path")
+	sectionMoveCmd.Flag("project").Annotations = map[string][]string{cobra.BashCompCustom: {"__todoist_project_id"}}
+	sectionCmd.AddCommand(sectionMoveCmd)
+	sectionCmd.AddCommand(sectionReorderCmd)
+	sectionCmd.AddCommand(sectionArchiveCmd)
+	sectionCmd.AddCommand(sectionUnarchiveCmd)
+	sectionCmd.AddCommand(sectionDeleteCmd)
+}
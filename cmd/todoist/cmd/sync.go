@@ -13,11 +13,25 @@ var syncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Syncronize origin server",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		full, err := cmd.Flags().GetBool("full")
+		if err != nil {
+			return err
+		}
 		client, err := util.NewClient()
 		if err != nil {
 			return err
 		}
-		if err = client.FullSync(context.Background(), []todoist.Command{}); err != nil {
+		ctx := context.Background()
+		// send the commands queued while offline first
+		if err = client.Commit(ctx); err != nil {
+			return err
+		}
+		if full {
+			err = client.FullSync(ctx, []todoist.Command{})
+		} else {
+			err = client.Sync(ctx, []todoist.Command{})
+		}
+		if err != nil {
 			return err
 		}
 		fmt.Printf("update sync token: %s", client.SyncToken)
@@ -26,5 +40,6 @@ var syncCmd = &cobra.Command{
 }
 
 func init() {
+	syncCmd.Flags().Bool("full", false, "discard the cache and retrieve all resources")
 	RootCmd.AddCommand(syncCmd)
 }
@@ -1,11 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"github.com/kobtea/go-todoist/cmd/util"
 	"github.com/kobtea/go-todoist/todoist"
 	"github.com/spf13/cobra"
-	"sort"
 )
 
 // todayCmd represents the today command
@@ -17,21 +17,62 @@ var todayCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		var items []todoist.Item
-		for _, i := range client.Item.FindByDueDate(todoist.Today()) {
-			if !i.IsChecked() {
-				items = append(items, i)
-			}
-		}
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].Due.Date.Before(items[j].Due.Date)
-		})
+		items := todayItems(client)
 		relations := client.Relation.Items(items)
-		fmt.Println(util.ItemTableString(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date }))
+		return printList(func() string {
+			return util.ItemTableString(items, relations, func(i todoist.Item) todoist.Time { return i.Due.Date })
+		}, util.ItemFields, util.NewItemRecords(items, relations))
+	},
+}
+
+var todayReorderCmd = &cobra.Command{
+	Use:   "reorder [id...]",
+	Short: "set the order of today's tasks",
+	Long:  "set the order of today's tasks. the given items come first in the order, and the others follow them.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
+			return util.ProcessIDs(args, func(ids []todoist.ID) error {
+				var items []todoist.Item
+				given := map[todoist.ID]bool{}
+				for _, id := range ids {
+					item := client.Item.Resolve(id)
+					if item == nil {
+						return fmt.Errorf("invalid item id: %s", id)
+					}
+					given[item.ID] = true
+					items = append(items, *item)
+				}
+				for _, item := range todayItems(client) {
+					if !given[item.ID] {
+						items = append(items, item)
+					}
+				}
+				for i := range items {
+					items[i].DayOrder = i + 1
+				}
+				return client.Item.UpdateDayOrders(items)
+			})
+		}); err != nil {
+			return err
+		}
+		fmt.Println("succeeded to reorder today's tasks")
 		return nil
 	},
 }
 
+// todayItems returns the unchecked items due today in the day order.
+func todayItems(client *todoist.Client) []todoist.Item {
+	var items []todoist.Item
+	for _, i := range client.Item.FindByDueDate(todoist.TodayIn(client.Location())) {
+		if !i.IsChecked() {
+			items = append(items, i)
+		}
+	}
+	client.Item.SortByDayOrder(items)
+	return items
+}
+
 func init() {
 	RootCmd.AddCommand(todayCmd)
+	todayCmd.AddCommand(todayReorderCmd)
 }
@@ -3,56 +3,122 @@ package util
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/kobtea/go-todoist/todoist"
 	"github.com/spf13/viper"
 	"io/ioutil"
 	"os"
+	"path"
 )
 
 type Config struct {
-	Token string `json:"token"`
+	Token    string `json:"token"`
+	CacheDir string `json:"cache_dir,omitempty"`
+	// Storage is a backend of the cache, "file" (default) or "kv".
+	Storage string `json:"storage,omitempty"`
+	// CacheKeyFile is a file to derive the key encrypting the cache.
+	// TODOIST_CACHE_PASSPHRASE is used instead if it is set.
+	CacheKeyFile string `json:"cache_key_file,omitempty"`
 }
 
-func resolveToken() string {
+func readConfig() Config {
+	var c Config
+	file := os.ExpandEnv("$HOME/.go-todoist/config.json")
+	if b, err := ioutil.ReadFile(file); err == nil {
+		if err = json.Unmarshal(b, &c); err != nil {
+			return Config{}
+		}
+	}
+	return c
+}
+
+func resolveToken(c Config) string {
 	if s := viper.GetString("TODOIST_TOKEN"); len(s) != 0 {
 		return s
 	}
-	file := os.ExpandEnv("$HOME/.go-todoist/config.json")
-	if b, err := ioutil.ReadFile(file); err != nil {
-		return ""
-	} else {
-		var c Config
-		if err = json.Unmarshal(b, &c); err != nil {
-			return ""
+	return c.Token
+}
+
+func resolveCacheDir(c Config) string {
+	if s := viper.GetString("TODOIST_CACHE_DIR"); len(s) != 0 {
+		return s
+	}
+	if len(c.CacheDir) != 0 {
+		return c.CacheDir
+	}
+	return "$HOME/.go-todoist"
+}
+
+func newStorage(config Config, cacheDir string) (todoist.Storage, error) {
+	var storage todoist.Storage
+	var err error
+	switch config.Storage {
+	case "", "file":
+		storage, err = todoist.NewFileStorage(cacheDir)
+	case "kv":
+		storage, err = todoist.NewKVFileStorage(path.Join(cacheDir, "cache.db"))
+	default:
+		err = fmt.Errorf("unknown storage: %s", config.Storage)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if passphrase := viper.GetString("TODOIST_CACHE_PASSPHRASE"); len(passphrase) != 0 {
+		return todoist.NewPassphraseStorage(storage, passphrase)
+	}
+	if len(config.CacheKeyFile) != 0 {
+		key, err := todoist.ReadKeyFile(os.ExpandEnv(config.CacheKeyFile))
+		if err != nil {
+			return nil, err
 		}
-		return c.Token
+		return todoist.NewEncryptedStorage(storage, key)
 	}
+	return storage, nil
 }
 
 func NewClient() (*todoist.Client, error) {
-	return todoist.NewClient(
-		"",
-		resolveToken(),
-		"*",
-		"",
-		nil)
+	config := readConfig()
+	token := resolveToken(config)
+	cacheDir := os.ExpandEnv(resolveCacheDir(config))
+	storage, err := newStorage(config, cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	if err = todoist.MigrateLegacyCache(cacheDir, token, storage); err != nil {
+		return nil, err
+	}
+	client, err := todoist.NewClientWithStorage("", token, "*", storage, nil)
+	if err != nil {
+		return nil, err
+	}
+	client.CacheDir = cacheDir
+	return client, nil
 }
 
-func AutoCommit(f func(client todoist.Client, ctx context.Context) error) error {
+func AutoCommit(f func(client *todoist.Client, ctx context.Context) error) error {
 	client, err := NewClient()
 	if err != nil {
 		return err
 	}
 	ctx := context.Background()
-	if err = f(*client, ctx); err != nil {
+	if err = f(client, ctx); err != nil {
 		return err
 
 	}
-	if err = client.Commit(ctx); err != nil {
+	if err = Commit(client, ctx); err != nil {
 		return err
 	}
-	if err = client.FullSync(ctx, []todoist.Command{}); err != nil {
+	return nil
+}
+
+// Commit sends the queued commands.
+// If they cannot be sent temporarily, e.g. offline, they are kept in the cache directory
+// and sent on the next commit or sync. Other errors are returned.
+func Commit(client *todoist.Client, ctx context.Context) error {
+	err := client.Commit(ctx)
+	if err == nil || !todoist.IsTemporary(err) {
 		return err
 	}
+	fmt.Fprintf(os.Stderr, "failed to send %d command(s), they are queued until the next sync: %s\n", len(client.Queue()), err)
 	return nil
 }
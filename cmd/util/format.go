@@ -1,12 +1,15 @@
 package util
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/kobtea/go-todoist/todoist"
 	"github.com/mattn/go-runewidth"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func StringWidthWithoutColor(s string) int {
@@ -71,8 +74,41 @@ func FilterTableString(filters []todoist.Filter) string {
 }
 
 func ItemTableString(items []todoist.Item, relations todoist.ItemRelations, f func(item todoist.Item) todoist.Time) string {
-	var rows [][]todoist.ColorStringer
+	var contents []string
 	for _, i := range items {
+		contents = append(contents, i.Content)
+	}
+	return itemTableString(items, contents, relations, f)
+}
+
+// ItemTreeTableString shows the items as indented trees.
+// Sub items of collapsed items are hidden with the number of them unless expand is true.
+func ItemTreeTableString(items []todoist.Item, relations todoist.ItemRelations, f func(item todoist.Item) todoist.Time, expand bool) string {
+	items, contents := flattenItemForest(todoist.BuildItemForest(items), expand)
+	return itemTableString(items, contents, relations, f)
+}
+
+func flattenItemForest(forest []*todoist.ItemNode, expand bool) ([]todoist.Item, []string) {
+	var items []todoist.Item
+	var contents []string
+	for _, root := range forest {
+		root.Walk(func(node *todoist.ItemNode) bool {
+			content := strings.Repeat("  ", node.Depth) + node.Item.Content
+			folded := !expand && node.Item.Collapsed.Bool() && len(node.Children) != 0
+			if folded {
+				content += fmt.Sprintf(" (+%d)", node.Count())
+			}
+			items = append(items, node.Item)
+			contents = append(contents, content)
+			return !folded
+		})
+	}
+	return items, contents
+}
+
+func itemTableString(items []todoist.Item, contents []string, relations todoist.ItemRelations, f func(item todoist.Item) todoist.Time) string {
+	var rows [][]todoist.ColorStringer
+	for n, i := range items {
 		project := todoist.Project{}
 		if v, ok := relations.Projects[i.ProjectID]; ok {
 			project = v
@@ -85,30 +121,154 @@ func ItemTableString(items []todoist.Item, relations todoist.ItemRelations, f fu
 		}
 		rows = append(rows, []todoist.ColorStringer{
 			todoist.NewNoColorString(i.ID.String()),
-			f(i),
+			f(i).In(relations.Location),
 			todoist.NewNoColorString(strconv.Itoa(i.Priority)),
 			project,
 			labels,
-			todoist.NewNoColorString(i.Content),
+			relations.Users[i.ResponsibleUID],
+			todoist.NewNoColorString(contents[n]),
 		})
 	}
 	return TableString(rows)
 }
 
+// ItemTableStringBySection groups the items by their sections with a header line of each section.
+// Items without sections come first, and the sections are ordered in each project.
+// Items in each section are shown as trees like ItemTreeTableString.
+func ItemTableStringBySection(items []todoist.Item, relations todoist.ItemRelations, f func(item todoist.Item) todoist.Time, expand bool) string {
+	keys, groups := groupItemsBySection(items, relations)
+	var sorted []todoist.Item
+	var contents []string
+	for _, key := range keys {
+		groupItems, groupContents := flattenItemForest(todoist.BuildItemForest(groups[key]), expand)
+		groups[key] = groupItems
+		sorted = append(sorted, groupItems...)
+		contents = append(contents, groupContents...)
+	}
+	lines := strings.Split(itemTableString(sorted, contents, relations, f), "\n")
+	var res []string
+	n := 0
+	for _, key := range keys {
+		if !key.IsZero() {
+			header := "/" + key.String()
+			if section, ok := relations.Sections[key]; ok {
+				header = relations.Projects[section.ProjectID].ColorString() + section.ColorString()
+			}
+			if n > 0 {
+				res = append(res, "")
+			}
+			res = append(res, header)
+		}
+		res = append(res, lines[n:n+len(groups[key])]...)
+		n += len(groups[key])
+	}
+	return strings.Join(res, "\n")
+}
+
+// SortItemsBySection returns the items in the order of ItemTableStringBySection with all sub items.
+func SortItemsBySection(items []todoist.Item, relations todoist.ItemRelations) []todoist.Item {
+	keys, groups := groupItemsBySection(items, relations)
+	var sorted []todoist.Item
+	for _, key := range keys {
+		groupItems, _ := flattenItemForest(todoist.BuildItemForest(groups[key]), true)
+		sorted = append(sorted, groupItems...)
+	}
+	return sorted
+}
+
+// groupItemsBySection returns the section ids in the order of the headers and the items of each section.
+func groupItemsBySection(items []todoist.Item, relations todoist.ItemRelations) ([]todoist.ID, map[todoist.ID][]todoist.Item) {
+	projectOrder := map[todoist.ID]int{}
+	var keys []todoist.ID
+	groups := map[todoist.ID][]todoist.Item{}
+	for _, i := range items {
+		if _, ok := projectOrder[i.ProjectID]; !ok {
+			projectOrder[i.ProjectID] = len(projectOrder)
+		}
+		if _, ok := groups[i.SectionID]; !ok {
+			keys = append(keys, i.SectionID)
+		}
+		groups[i.SectionID] = append(groups[i.SectionID], i)
+	}
+	sort.SliceStable(keys, func(a, b int) bool {
+		if keys[a].IsZero() || keys[b].IsZero() {
+			return keys[a].IsZero() && !keys[b].IsZero()
+		}
+		pa := projectOrder[groups[keys[a]][0].ProjectID]
+		pb := projectOrder[groups[keys[b]][0].ProjectID]
+		if pa != pb {
+			return pa < pb
+		}
+		return relations.Sections[keys[a]].SectionOrder < relations.Sections[keys[b]].SectionOrder
+	})
+	return keys, groups
+}
+
 func ProjectTableString(projects []todoist.Project) string {
+	return projectTreeTableString(projects, nil, true)
+}
+
+// ProjectTreeTableString shows the projects as trees.
+// Sub projects of collapsed projects are hidden with the number of them unless expand is true.
+func ProjectTreeTableString(projects []todoist.Project, expand bool) string {
+	return projectTreeTableString(projects, nil, expand)
+}
+
+// ProjectSectionTableString shows the projects as trees with the sections of each project under it.
+// Sub projects of collapsed projects are hidden with the number of them unless expand is true.
+func ProjectSectionTableString(projects []todoist.Project, sections func(id todoist.ID) []todoist.Section, expand bool) string {
+	return projectTreeTableString(projects, sections, expand)
+}
+
+func projectTreeTableString(projects []todoist.Project, sections func(id todoist.ID) []todoist.Section, expand bool) string {
 	var rows [][]todoist.ColorStringer
-	indentMaps := map[string]int{}
-	for _, p := range projects {
-		indent := 0
-		if !p.ParentID.IsZero() {
-			if i, ok := indentMaps[p.ParentID.String()]; ok {
-				indent = i + 1
+	for _, root := range todoist.BuildProjectForest(projects) {
+		root.Walk(func(node *todoist.ProjectNode) bool {
+			p := node.Project
+			indent := strings.Repeat("  ", node.Depth)
+			name := indent + p.ColorString()
+			if p.IsArchived.Bool() {
+				name += " [archived]"
+			}
+			folded := !expand && p.Collapsed.Bool() && len(node.Children) != 0
+			if folded {
+				name += fmt.Sprintf(" (+%d)", node.Count())
+			} else if p.Collapsed.Bool() {
+				name += " [collapsed]"
+			}
+			rows = append(rows, []todoist.ColorStringer{
+				todoist.NewNoColorString(p.ID.String()),
+				todoist.NewNoColorString(name),
+			})
+			if sections != nil {
+				ss := sections(p.ID)
+				sort.SliceStable(ss, func(i, j int) bool {
+					return ss[i].SectionOrder < ss[j].SectionOrder
+				})
+				for _, s := range ss {
+					rows = append(rows, []todoist.ColorStringer{
+						todoist.NewNoColorString(s.ID.String()),
+						todoist.NewNoColorString(indent + "  " + s.ColorString()),
+					})
+				}
 			}
+			return !folded
+		})
+	}
+	return TableString(rows)
+}
+
+func SectionTableString(sections []todoist.Section, resolveProject func(id todoist.ID) *todoist.Project) string {
+	var rows [][]todoist.ColorStringer
+	for _, s := range sections {
+		project := todoist.Project{}
+		if p := resolveProject(s.ProjectID); p != nil {
+			project = *p
 		}
-		indentMaps[p.ID.String()] = indent
 		rows = append(rows, []todoist.ColorStringer{
-			todoist.NewNoColorString(p.ID.String()),
-			todoist.NewNoColorString(strings.Repeat(" ", indent) + p.ColorString()),
+			todoist.NewNoColorString(s.ID.String()),
+			project,
+			s,
 		})
 	}
 	return TableString(rows)
@@ -127,3 +287,56 @@ func LabelTableString(labels []todoist.Label) string {
 	}
 	return TableString(rows)
 }
+
+// ReminderTableString shows the reminders with the contents of their items, and the due dates in the timezone.
+func ReminderTableString(reminders []todoist.Reminder, resolveItem func(id todoist.ID) *todoist.Item, loc *time.Location) string {
+	var rows [][]todoist.ColorStringer
+	for _, r := range reminders {
+		content := ""
+		if item := resolveItem(r.ItemID); item != nil {
+			content = item.Content
+		}
+		if r.Due != nil {
+			due := *r.Due
+			due.Date = due.Date.In(loc)
+			r.Due = &due
+		}
+		rows = append(rows, []todoist.ColorStringer{
+			todoist.NewNoColorString(r.ID.String()),
+			todoist.NewNoColorString(r.ItemID.String()),
+			todoist.NewNoColorString(r.Type),
+			todoist.NewNoColorString(r.String()),
+			todoist.NewNoColorString(content),
+		})
+	}
+	return TableString(rows)
+}
+
+func NotificationTableString(notifications []todoist.LiveNotification, relations todoist.NotificationRelations) string {
+	var rows [][]todoist.ColorStringer
+	for _, n := range notifications {
+		rows = append(rows, []todoist.ColorStringer{
+			todoist.NewNoColorString(n.ID.String()),
+			todoist.NewNoColorString(n.CreatedDate.In(relations.Location).String()),
+			todoist.NewNoColorString(n.NotificationType),
+			todoist.NewNoColorString(n.Message(relations)),
+		})
+	}
+	return TableString(rows)
+}
+
+func CommandTableString(commands []todoist.Command) string {
+	var rows [][]todoist.ColorStringer
+	for _, c := range commands {
+		args, err := json.Marshal(c.Args)
+		if err != nil {
+			args = []byte(err.Error())
+		}
+		rows = append(rows, []todoist.ColorStringer{
+			todoist.NewNoColorString(string(c.UUID)),
+			todoist.NewNoColorString(c.Type),
+			todoist.NewNoColorString(string(args)),
+		})
+	}
+	return TableString(rows)
+}
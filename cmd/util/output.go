@@ -0,0 +1,432 @@
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/kobtea/go-todoist/todoist"
+	"gopkg.in/yaml.v2"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OutputFormats are the formats of the listings.
+// "table" is for humans, and the others print the records with stable field names for scripts.
+var OutputFormats = []string{"table", "json", "jsonl", "yaml", "csv", "tsv"}
+
+// Record is a row of the structured output.
+// Values returns the fields in the order of the header of the record type, e.g. ItemFields.
+type Record interface {
+	Values() []string
+}
+
+// WriteRecords writes the records in the format except for "table".
+// The header is written in csv and tsv even if there are no records.
+func WriteRecords(w io.Writer, format string, header []string, records []Record) error {
+	switch format {
+	case "json":
+		if records == nil {
+			records = []Record{}
+		}
+		b, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "yaml":
+		if records == nil {
+			records = []Record{}
+		}
+		b, err := yaml.Marshal(records)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	case "csv", "tsv":
+		cw := csv.NewWriter(w)
+		if format == "tsv" {
+			cw.Comma = '\t'
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		for _, r := range records {
+			if err := cw.Write(r.Values()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown output format: %s, use one of %s", format, strings.Join(OutputFormats, ", "))
+	}
+}
+
+// formatTime formats the time in the timezone of the account,
+// as a date for full day times and RFC 3339 for the others.
+func formatTime(t todoist.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	t = t.In(loc)
+	if t.IsFullDay() {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatID(id todoist.ID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.String()
+}
+
+// ItemFields is the header of ItemRecord.
+var ItemFields = []string{
+	"id", "parent_id", "content", "project_id", "project", "section_id", "section", "labels",
+	"priority", "due", "due_string", "recurring", "responsible", "checked", "completed",
+}
+
+// ItemRecord is an item with the names of the project, section, labels and responsible user.
+type ItemRecord struct {
+	ID        string `json:"id" yaml:"id"`
+	ParentID  string `json:"parent_id" yaml:"parent_id"`
+	Content   string `json:"content" yaml:"content"`
+	ProjectID string `json:"project_id" yaml:"project_id"`
+	Project   string `json:"project" yaml:"project"`
+	SectionID string `json:"section_id" yaml:"section_id"`
+	Section   string `json:"section" yaml:"section"`
+	// Labels are the names of the labels.
+	Labels []string `json:"labels" yaml:"labels"`
+	// Priority is 1 for the highest (p1) to 4, unlike the api.
+	Priority    int    `json:"priority" yaml:"priority"`
+	Due         string `json:"due" yaml:"due"`
+	DueString   string `json:"due_string" yaml:"due_string"`
+	Recurring   bool   `json:"recurring" yaml:"recurring"`
+	Responsible string `json:"responsible" yaml:"responsible"`
+	Checked     bool   `json:"checked" yaml:"checked"`
+	Completed   string `json:"completed" yaml:"completed"`
+}
+
+func (r ItemRecord) Values() []string {
+	return []string{
+		r.ID, r.ParentID, r.Content, r.ProjectID, r.Project, r.SectionID, r.Section, strings.Join(r.Labels, ","),
+		strconv.Itoa(r.Priority), r.Due, r.DueString, strconv.FormatBool(r.Recurring), r.Responsible,
+		strconv.FormatBool(r.Checked), r.Completed,
+	}
+}
+
+func NewItemRecord(item todoist.Item, relations todoist.ItemRelations) ItemRecord {
+	labels := []string{}
+	for _, lid := range item.Labels {
+		if v, ok := relations.Labels[lid]; ok {
+			labels = append(labels, v.Name)
+		}
+	}
+	priority := 4
+	if 1 <= item.Priority && item.Priority <= 4 {
+		priority = 5 - item.Priority
+	}
+	var responsible string
+	if !item.ResponsibleUID.IsZero() {
+		responsible = relations.Users[item.ResponsibleUID].String()
+	}
+	return ItemRecord{
+		ID:          item.ID.String(),
+		ParentID:    formatID(item.ParentID),
+		Content:     item.Content,
+		ProjectID:   formatID(item.ProjectID),
+		Project:     relations.Projects[item.ProjectID].Name,
+		SectionID:   formatID(item.SectionID),
+		Section:     relations.Sections[item.SectionID].Name,
+		Labels:      labels,
+		Priority:    priority,
+		Due:         formatTime(item.Due.Date, relations.Location),
+		DueString:   item.Due.String,
+		Recurring:   item.Due.IsRecurring,
+		Responsible: responsible,
+		Checked:     item.IsChecked(),
+		Completed:   formatTime(item.CompletedDate, relations.Location),
+	}
+}
+
+func NewItemRecords(items []todoist.Item, relations todoist.ItemRelations) []Record {
+	var records []Record
+	for _, i := range items {
+		records = append(records, NewItemRecord(i, relations))
+	}
+	return records
+}
+
+// ViewItemFields is the header of ViewItemRecord.
+var ViewItemFields = append([]string{"view"}, ItemFields...)
+
+// ViewItemRecord is an item with the query of the filter view which it belongs to.
+type ViewItemRecord struct {
+	View       string `json:"view" yaml:"view"`
+	ItemRecord `yaml:",inline"`
+}
+
+func (r ViewItemRecord) Values() []string {
+	return append([]string{r.View}, r.ItemRecord.Values()...)
+}
+
+// ProjectFields is the header of ProjectRecord.
+var ProjectFields = []string{"id", "parent_id", "name", "path", "color", "archived", "collapsed", "sections"}
+
+// ProjectRecord is a project with the path from the root project and the names of the sections.
+type ProjectRecord struct {
+	ID        string   `json:"id" yaml:"id"`
+	ParentID  string   `json:"parent_id" yaml:"parent_id"`
+	Name      string   `json:"name" yaml:"name"`
+	Path      string   `json:"path" yaml:"path"`
+	Color     int      `json:"color" yaml:"color"`
+	Archived  bool     `json:"archived" yaml:"archived"`
+	Collapsed bool     `json:"collapsed" yaml:"collapsed"`
+	Sections  []string `json:"sections" yaml:"sections"`
+}
+
+func (r ProjectRecord) Values() []string {
+	return []string{
+		r.ID, r.ParentID, r.Name, r.Path, strconv.Itoa(r.Color),
+		strconv.FormatBool(r.Archived), strconv.FormatBool(r.Collapsed), strings.Join(r.Sections, ","),
+	}
+}
+
+// NewProjectRecords returns the records of the projects in the order of the trees like ProjectSectionTableString.
+func NewProjectRecords(projects []todoist.Project, path func(id todoist.ID) string, sections func(id todoist.ID) []todoist.Section) []Record {
+	var records []Record
+	for _, root := range todoist.BuildProjectForest(projects) {
+		root.Walk(func(node *todoist.ProjectNode) bool {
+			p := node.Project
+			ss := sections(p.ID)
+			sort.SliceStable(ss, func(i, j int) bool {
+				return ss[i].SectionOrder < ss[j].SectionOrder
+			})
+			names := []string{}
+			for _, s := range ss {
+				names = append(names, s.Name)
+			}
+			records = append(records, ProjectRecord{
+				ID:        p.ID.String(),
+				ParentID:  formatID(p.ParentID),
+				Name:      p.Name,
+				Path:      path(p.ID),
+				Color:     p.Color,
+				Archived:  p.IsArchived.Bool(),
+				Collapsed: p.Collapsed.Bool(),
+				Sections:  names,
+			})
+			return true
+		})
+	}
+	return records
+}
+
+// LabelFields is the header of LabelRecord.
+var LabelFields = []string{"id", "name", "color", "order"}
+
+type LabelRecord struct {
+	ID    string `json:"id" yaml:"id"`
+	Name  string `json:"name" yaml:"name"`
+	Color int    `json:"color" yaml:"color"`
+	Order int    `json:"order" yaml:"order"`
+}
+
+func (r LabelRecord) Values() []string {
+	return []string{r.ID, r.Name, strconv.Itoa(r.Color), strconv.Itoa(r.Order)}
+}
+
+func NewLabelRecords(labels []todoist.Label) []Record {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].ItemOrder < labels[j].ItemOrder
+	})
+	var records []Record
+	for _, l := range labels {
+		records = append(records, LabelRecord{ID: l.ID.String(), Name: l.Name, Color: l.Color, Order: l.ItemOrder})
+	}
+	return records
+}
+
+// FilterFields is the header of FilterRecord.
+var FilterFields = []string{"id", "name", "query", "color", "order"}
+
+type FilterRecord struct {
+	ID    string `json:"id" yaml:"id"`
+	Name  string `json:"name" yaml:"name"`
+	Query string `json:"query" yaml:"query"`
+	Color int    `json:"color" yaml:"color"`
+	Order int    `json:"order" yaml:"order"`
+}
+
+func (r FilterRecord) Values() []string {
+	return []string{r.ID, r.Name, r.Query, strconv.Itoa(r.Color), strconv.Itoa(r.Order)}
+}
+
+func NewFilterRecords(filters []todoist.Filter) []Record {
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].ItemOrder < filters[j].ItemOrder
+	})
+	var records []Record
+	for _, f := range filters {
+		records = append(records, FilterRecord{ID: f.ID.String(), Name: f.Name, Query: f.Query, Color: f.Color, Order: f.ItemOrder})
+	}
+	return records
+}
+
+// SectionFields is the header of SectionRecord.
+var SectionFields = []string{"id", "project_id", "project", "name", "order", "collapsed", "archived"}
+
+type SectionRecord struct {
+	ID        string `json:"id" yaml:"id"`
+	ProjectID string `json:"project_id" yaml:"project_id"`
+	Project   string `json:"project" yaml:"project"`
+	Name      string `json:"name" yaml:"name"`
+	Order     int    `json:"order" yaml:"order"`
+	Collapsed bool   `json:"collapsed" yaml:"collapsed"`
+	Archived  bool   `json:"archived" yaml:"archived"`
+}
+
+func (r SectionRecord) Values() []string {
+	return []string{
+		r.ID, r.ProjectID, r.Project, r.Name, strconv.Itoa(r.Order),
+		strconv.FormatBool(r.Collapsed), strconv.FormatBool(r.Archived),
+	}
+}
+
+func NewSectionRecords(sections []todoist.Section, resolveProject func(id todoist.ID) *todoist.Project) []Record {
+	var records []Record
+	for _, s := range sections {
+		var project string
+		if p := resolveProject(s.ProjectID); p != nil {
+			project = p.Name
+		}
+		records = append(records, SectionRecord{
+			ID:        s.ID.String(),
+			ProjectID: formatID(s.ProjectID),
+			Project:   project,
+			Name:      s.Name,
+			Order:     s.SectionOrder,
+			Collapsed: s.Collapsed.Bool(),
+			Archived:  s.IsArchived.Bool(),
+		})
+	}
+	return records
+}
+
+// ReminderFields is the header of ReminderRecord.
+var ReminderFields = []string{"id", "item_id", "item", "type", "due", "mm_offset", "location", "trigger"}
+
+// ReminderRecord is a reminder with the content of the item.
+// The fields which do not apply to the type of the reminder are empty.
+type ReminderRecord struct {
+	ID       string `json:"id" yaml:"id"`
+	ItemID   string `json:"item_id" yaml:"item_id"`
+	Item     string `json:"item" yaml:"item"`
+	Type     string `json:"type" yaml:"type"`
+	Due      string `json:"due" yaml:"due"`
+	MmOffset int    `json:"mm_offset" yaml:"mm_offset"`
+	Location string `json:"location" yaml:"location"`
+	Trigger  string `json:"trigger" yaml:"trigger"`
+}
+
+func (r ReminderRecord) Values() []string {
+	return []string{r.ID, r.ItemID, r.Item, r.Type, r.Due, strconv.Itoa(r.MmOffset), r.Location, r.Trigger}
+}
+
+func NewReminderRecords(reminders []todoist.Reminder, resolveItem func(id todoist.ID) *todoist.Item, loc *time.Location) []Record {
+	var records []Record
+	for _, r := range reminders {
+		record := ReminderRecord{
+			ID:       r.ID.String(),
+			ItemID:   formatID(r.ItemID),
+			Type:     r.Type,
+			MmOffset: r.MmOffset,
+			Location: r.Name,
+			Trigger:  r.LocTrigger,
+		}
+		if item := resolveItem(r.ItemID); item != nil {
+			record.Item = item.Content
+		}
+		if r.Due != nil {
+			record.Due = formatTime(r.Due.Date, loc)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// NotificationFields is the header of NotificationRecord.
+var NotificationFields = []string{"id", "created", "type", "message", "unread", "project_id", "item_id"}
+
+// NotificationRecord is a live notification with the message of it.
+type NotificationRecord struct {
+	ID        string `json:"id" yaml:"id"`
+	Created   string `json:"created" yaml:"created"`
+	Type      string `json:"type" yaml:"type"`
+	Message   string `json:"message" yaml:"message"`
+	Unread    bool   `json:"unread" yaml:"unread"`
+	ProjectID string `json:"project_id" yaml:"project_id"`
+	ItemID    string `json:"item_id" yaml:"item_id"`
+}
+
+func (r NotificationRecord) Values() []string {
+	return []string{r.ID, r.Created, r.Type, r.Message, strconv.FormatBool(r.Unread), r.ProjectID, r.ItemID}
+}
+
+func NewNotificationRecords(notifications []todoist.LiveNotification, relations todoist.NotificationRelations) []Record {
+	var records []Record
+	for _, n := range notifications {
+		records = append(records, NotificationRecord{
+			ID:        n.ID.String(),
+			Created:   formatTime(n.CreatedDate, relations.Location),
+			Type:      n.NotificationType,
+			Message:   n.Message(relations),
+			Unread:    n.IsUnread.Bool(),
+			ProjectID: formatID(n.ProjectID),
+			ItemID:    formatID(n.ItemID),
+		})
+	}
+	return records
+}
+
+// CommandFields is the header of CommandRecord.
+var CommandFields = []string{"uuid", "type", "temp_id", "args"}
+
+// CommandRecord is a queued command. The args are json in csv and tsv.
+type CommandRecord struct {
+	UUID   string      `json:"uuid" yaml:"uuid"`
+	Type   string      `json:"type" yaml:"type"`
+	TempID string      `json:"temp_id" yaml:"temp_id"`
+	Args   interface{} `json:"args" yaml:"args"`
+}
+
+func (r CommandRecord) Values() []string {
+	args, err := json.Marshal(r.Args)
+	if err != nil {
+		args = []byte(err.Error())
+	}
+	return []string{r.UUID, r.Type, r.TempID, string(args)}
+}
+
+func NewCommandRecords(commands []todoist.Command) []Record {
+	var records []Record
+	for _, c := range commands {
+		records = append(records, CommandRecord{UUID: string(c.UUID), Type: c.Type, TempID: formatID(c.TempID), Args: c.Args})
+	}
+	return records
+}
@@ -13,4 +13,8 @@ require (
 	github.com/spf13/cobra v0.0.4-0.20180821161202-6fd8e29b07d8
 	github.com/spf13/viper v1.2.0
 	github.com/stretchr/testify v1.5.1 // indirect
+	go.etcd.io/bbolt v1.3.5
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/sys v0.0.0-20200202164722-d101bd2416d5
+	gopkg.in/yaml.v2 v2.2.2
 )
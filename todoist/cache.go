@@ -0,0 +1,143 @@
+package todoist
+
+import "sort"
+
+// orderedIDs keeps ids in insertion order with O(1) addition and removal.
+// Removed ids leave holes, which are compacted when they exceed half of the slots.
+type orderedIDs struct {
+	ids     []ID
+	pos     map[ID]int
+	removed int
+}
+
+func newOrderedIDs() *orderedIDs {
+	return &orderedIDs{pos: map[ID]int{}}
+}
+
+func (o *orderedIDs) add(id ID) {
+	if _, ok := o.pos[id]; ok {
+		return
+	}
+	o.pos[id] = len(o.ids)
+	o.ids = append(o.ids, id)
+}
+
+func (o *orderedIDs) remove(id ID) {
+	p, ok := o.pos[id]
+	if !ok {
+		return
+	}
+	delete(o.pos, id)
+	o.ids[p] = ""
+	o.removed++
+	if o.removed > len(o.ids)/2 {
+		o.compact()
+	}
+}
+
+// rename replaces the id keeping its position.
+func (o *orderedIDs) rename(from, to ID) {
+	p, ok := o.pos[from]
+	if !ok || from == to {
+		return
+	}
+	if _, ok := o.pos[to]; ok {
+		o.remove(from)
+		return
+	}
+	delete(o.pos, from)
+	o.pos[to] = p
+	o.ids[p] = to
+}
+
+func (o *orderedIDs) compact() {
+	ids := make([]ID, 0, len(o.pos))
+	for _, id := range o.ids {
+		if len(id) != 0 {
+			o.pos[id] = len(ids)
+			ids = append(ids, id)
+		}
+	}
+	o.ids = ids
+	o.removed = 0
+}
+
+func (o *orderedIDs) list() []ID {
+	res := make([]ID, 0, len(o.pos))
+	for _, id := range o.ids {
+		if len(id) != 0 {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// tempIDTargets returns the ids which are temporary ids of the mapping or indexed by them,
+// i.e. the entities which have or refer to the temporary ids, in insertion order.
+// The indexes are the references which may be temporary ids, such as project ids of items.
+func (o *orderedIDs) tempIDTargets(mapping TempIDMapping, indexes ...idIndex) []ID {
+	if len(mapping) == 0 {
+		return nil
+	}
+	seen := map[ID]struct{}{}
+	var ids []ID
+	add := func(id ID) {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	for tempID := range mapping {
+		if _, ok := o.pos[tempID]; ok {
+			add(tempID)
+		}
+		for _, index := range indexes {
+			for _, id := range index.get(tempID) {
+				add(id)
+			}
+		}
+	}
+	o.sort(ids)
+	return ids
+}
+
+// sort orders the ids by insertion order in place.
+func (o *orderedIDs) sort(ids []ID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return o.pos[ids[i]] < o.pos[ids[j]]
+	})
+}
+
+// idIndex is a secondary index from a key, such as a project id, to ids of entities.
+type idIndex map[ID]map[ID]struct{}
+
+func (x idIndex) add(key, id ID) {
+	if key.IsZero() {
+		return
+	}
+	ids, ok := x[key]
+	if !ok {
+		ids = map[ID]struct{}{}
+		x[key] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func (x idIndex) remove(key, id ID) {
+	ids, ok := x[key]
+	if !ok {
+		return
+	}
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(x, key)
+	}
+}
+
+func (x idIndex) get(key ID) []ID {
+	var res []ID
+	for id := range x[key] {
+		res = append(res, id)
+	}
+	return res
+}
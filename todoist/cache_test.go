@@ -0,0 +1,254 @@
+package todoist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderedIDs(t *testing.T) {
+	o := newOrderedIDs()
+	for _, id := range []ID{"1", "2", "3", "4", "2"} {
+		o.add(id)
+	}
+	o.remove("3")
+	o.rename("1", "5")
+	o.rename("2", "2")
+	expect := []ID{"5", "2", "4"}
+	if ids := o.list(); !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+
+	// compaction keeps the order
+	o.remove("5")
+	o.remove("2")
+	o.add("6")
+	expect = []ID{"4", "6"}
+	if ids := o.list(); !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+	ids := []ID{"6", "4"}
+	o.sort(ids)
+	if !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+}
+
+func TestOrderedIDs_tempIDTargets(t *testing.T) {
+	o := newOrderedIDs()
+	byProject := idIndex{}
+	for _, id := range []ID{"1", "tmp-2", "3", "4"} {
+		o.add(id)
+	}
+	byProject.add("tmp-p", "4")
+	byProject.add("tmp-p", "1")
+	byProject.add("100", "3")
+	if ids := o.tempIDTargets(TempIDMapping{}, byProject); ids != nil {
+		t.Errorf("Expect nil, but got %v", ids)
+	}
+	expect := []ID{"1", "tmp-2", "4"}
+	mapping := TempIDMapping{"tmp-2": "2", "tmp-p": "200", "tmp-unknown": "5"}
+	if ids := o.tempIDTargets(mapping, byProject); !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+}
+
+func TestProjectCache(t *testing.T) {
+	c := newProjectCache()
+	for _, p := range []Project{
+		{Entity: Entity{ID: "1"}, Name: "inbox"},
+		{Entity: Entity{ID: "tmp-2"}, Name: "parent"},
+		{Entity: Entity{ID: "3"}, Name: "child", ParentID: "tmp-2"},
+		{Entity: Entity{ID: "4"}, Name: "deleted"},
+		{Entity: Entity{ID: "4", IsDeleted: true}},
+	} {
+		c.store(p)
+	}
+	c.replaceTempIDs(TempIDMapping{"tmp-2": "2"})
+
+	var names []string
+	for _, p := range c.getAll() {
+		names = append(names, p.Name)
+	}
+	if expect := []string{"inbox", "parent", "child"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("Expect %v, but got %v", expect, names)
+	}
+	if c.resolve("tmp-2") != nil || c.resolve("2") == nil {
+		t.Errorf("Expect the temporary id to be replaced, but got %v", c.getAll())
+	}
+	if got := c.find(c.byParent, []ID{"tmp-2"}); len(got) != 0 {
+		t.Errorf("Expect no children of the temporary id, but got %v", got)
+	}
+	if got := c.find(c.byParent, []ID{"2"}); len(got) != 1 || got[0].ID != "3" || got[0].ParentID != "2" {
+		t.Errorf("Expect the child with the new parent id, but got %v", got)
+	}
+
+	c.remove(Project{Entity: Entity{ID: "3"}})
+	if got := c.find(c.byParent, []ID{"2"}); len(got) != 0 {
+		t.Errorf("Expect no children after remove, but got %v", got)
+	}
+}
+
+func TestSectionCache(t *testing.T) {
+	c := newSectionCache()
+	c.store(Section{Entity: Entity{ID: "1"}, ProjectID: "100"})
+	c.store(Section{Entity: Entity{ID: "tmp-2"}, ProjectID: "tmp-p"})
+	c.store(Section{Entity: Entity{ID: "3"}, ProjectID: "tmp-p"})
+	c.replaceTempIDs(TempIDMapping{"tmp-2": "2", "tmp-p": "200"})
+
+	tests := []struct {
+		sections []Section
+		expect   []ID
+	}{
+		{c.getAll(), []ID{"1", "2", "3"}},
+		{c.find(c.byProject, []ID{"tmp-p"}), nil},
+		{c.find(c.byProject, []ID{"200"}), []ID{"2", "3"}},
+		{c.find(c.byProject, []ID{"100"}), []ID{"1"}},
+	}
+	for i, test := range tests {
+		var ids []ID
+		for _, s := range test.sections {
+			ids = append(ids, s.ID)
+		}
+		if !reflect.DeepEqual(ids, test.expect) {
+			t.Errorf("%d. Expect %v, but got %v", i, test.expect, ids)
+		}
+	}
+
+	c.remove(Section{Entity: Entity{ID: "2"}})
+	if got := c.find(c.byProject, []ID{"200"}); len(got) != 1 || got[0].ID != "3" {
+		t.Errorf("Expect only section 3 after remove, but got %v", got)
+	}
+}
+
+func TestNoteCache(t *testing.T) {
+	c := newNoteCache()
+	c.store(Note{Entity: Entity{ID: "1"}, ItemID: "10", ProjectID: "100"})
+	c.store(Note{Entity: Entity{ID: "tmp-2"}, ItemID: "tmp-i", ProjectID: "100"})
+	c.store(Note{Entity: Entity{ID: "3"}, ProjectID: "tmp-p"})
+	c.replaceTempIDs(TempIDMapping{"tmp-2": "2", "tmp-i": "20", "tmp-p": "300"})
+
+	tests := []struct {
+		notes  []Note
+		expect []ID
+	}{
+		{c.getAll(), []ID{"1", "2", "3"}},
+		{c.find(c.byItem, []ID{"tmp-i"}), nil},
+		{c.find(c.byItem, []ID{"20"}), []ID{"2"}},
+		{c.find(c.byProject, []ID{"tmp-p"}), nil},
+		{c.find(c.byProject, []ID{"300"}), []ID{"3"}},
+		{c.find(c.byProject, []ID{"100"}), []ID{"1", "2"}},
+	}
+	for i, test := range tests {
+		var ids []ID
+		for _, n := range test.notes {
+			ids = append(ids, n.ID)
+		}
+		if !reflect.DeepEqual(ids, test.expect) {
+			t.Errorf("%d. Expect %v, but got %v", i, test.expect, ids)
+		}
+	}
+
+	c.remove(Note{Entity: Entity{ID: "2"}})
+	if got := c.find(c.byItem, []ID{"20"}); len(got) != 0 {
+		t.Errorf("Expect no notes after remove, but got %v", got)
+	}
+}
+
+func TestReminderCache(t *testing.T) {
+	c := newReminderCache()
+	c.store(Reminder{Entity: Entity{ID: "1"}, ItemID: "10"})
+	c.store(Reminder{Entity: Entity{ID: "tmp-2"}, ItemID: "tmp-i"})
+	c.store(Reminder{Entity: Entity{ID: "3"}, ItemID: "tmp-i"})
+	c.replaceTempIDs(TempIDMapping{"tmp-2": "2", "tmp-i": "20"})
+
+	var ids []ID
+	for _, r := range c.find(c.byItem, []ID{"20", "tmp-i"}) {
+		if r.ItemID != "20" {
+			t.Errorf("Expect item id 20, but got %v", r.ItemID)
+		}
+		ids = append(ids, r.ID)
+	}
+	if expect := []ID{"2", "3"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+	if c.resolve("tmp-2") != nil || c.resolve("2") == nil {
+		t.Errorf("Expect the temporary id to be replaced, but got %v", c.getAll())
+	}
+
+	c.remove(Reminder{Entity: Entity{ID: "3"}})
+	if got := c.find(c.byItem, []ID{"20"}); len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("Expect only reminder 2 after remove, but got %v", got)
+	}
+}
+
+func TestLabelCache(t *testing.T) {
+	c := newLabelCache()
+	c.store(Label{Entity: Entity{ID: "tmp-1"}, Name: "a"})
+	c.store(Label{Entity: Entity{ID: "2"}, Name: "b"})
+	c.store(Label{Entity: Entity{ID: "3"}, Name: "c"})
+	c.remove(Label{Entity: Entity{ID: "2"}})
+	c.replaceTempIDs(TempIDMapping{})
+	if c.resolve("tmp-1") == nil {
+		t.Errorf("Expect an empty mapping to keep the temporary id, but got %v", c.getAll())
+	}
+	c.replaceTempIDs(TempIDMapping{"tmp-1": "1"})
+
+	var ids []ID
+	for _, l := range c.getAll() {
+		ids = append(ids, l.ID)
+	}
+	if expect := []ID{"1", "3"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+	if l := c.resolve("1"); l == nil || l.Name != "a" {
+		t.Errorf("Expect label a, but got %v", l)
+	}
+}
+
+func TestFilterCache(t *testing.T) {
+	c := newFilterCache()
+	c.store(Filter{Entity: Entity{ID: "1"}, Name: "a"})
+	c.store(Filter{Entity: Entity{ID: "tmp-2"}, Name: "b"})
+	c.store(Filter{Entity: Entity{ID: "1", IsDeleted: true}})
+	c.replaceTempIDs(TempIDMapping{"tmp-2": "2"})
+
+	var ids []ID
+	for _, f := range c.getAll() {
+		ids = append(ids, f.ID)
+	}
+	if expect := []ID{"2"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+	if f := c.resolve("2"); f == nil || f.Name != "b" {
+		t.Errorf("Expect filter b, but got %v", f)
+	}
+}
+
+func TestCollaboratorCache(t *testing.T) {
+	c := newCollaboratorCache()
+	c.store(Collaborator{Entity: Entity{ID: "10"}, Email: "a@example.com"})
+	c.store(Collaborator{Entity: Entity{ID: "20"}, Email: "b@example.com"})
+	c.remove(Collaborator{Entity: Entity{ID: "20"}})
+	c.storeState(CollaboratorState{ProjectID: "100", UserID: "10", State: CollaboratorStateActive})
+	c.storeState(CollaboratorState{ProjectID: "tmp-p", UserID: "10", State: CollaboratorStateInvited})
+	c.replaceTempIDs(TempIDMapping{"tmp-p": "200"})
+
+	if got := c.getAll(); len(got) != 1 || got[0].ID != "10" {
+		t.Errorf("Expect only collaborator 10, but got %v", got)
+	}
+	if got := c.findStates("tmp-p"); len(got) != 0 {
+		t.Errorf("Expect no states of the temporary id, but got %v", got)
+	}
+	expect := []CollaboratorState{
+		{ProjectID: "100", UserID: "10", State: CollaboratorStateActive},
+		{ProjectID: "200", UserID: "10", State: CollaboratorStateInvited},
+	}
+	if got := c.getAllStates(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expect %v, but got %v", expect, got)
+	}
+
+	c.storeState(CollaboratorState{ProjectID: "200", UserID: "10", IsDeleted: true})
+	if got := c.findStates("200"); len(got) != 0 {
+		t.Errorf("Expect no states after delete, but got %v", got)
+	}
+}
@@ -2,9 +2,10 @@ package todoist
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -12,27 +13,76 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
+	"time"
 )
 
+// DefaultBatchSize is the max number of commands in a sync request.
+const DefaultBatchSize = 100
+
+// Client is a client for the sync api.
+// It is safe for concurrent use by multiple goroutines, including its sub clients such as Item and Project.
+// Exported fields must not be modified after the client is used.
 type Client struct {
-	URL        *url.URL
-	HTTPClient *http.Client
-	Token      string
-	SyncToken  string
-	CacheDir   string
-	syncState  *SyncState
-	Logger     *log.Logger
-	Completed  *CompletedClient
-	Filter     *FilterClient
-	Item       *ItemClient
-	Label      *LabelClient
-	Project    *ProjectClient
-	Relation   *RelationClient
-	Note       *NoteClient
-	queue      []Command
+	URL          *url.URL
+	HTTPClient   *http.Client
+	Retry        RetryPolicy
+	BatchSize    int
+	Token        string
+	SyncToken    string
+	CacheDir     string
+	Storage      Storage
+	syncState    *SyncState
+	Logger       *log.Logger
+	Completed    *CompletedClient
+	Filter       *FilterClient
+	Item         *ItemClient
+	Label        *LabelClient
+	Project      *ProjectClient
+	Relation     *RelationClient
+	Note         *NoteClient
+	Reminder     *ReminderClient
+	Section      *SectionClient
+	Collaborator *CollaboratorClient
+	Notification *NotificationClient
+	User         *UserClient
+	queue        []Command
+	// queueBase is the set of the commands in the stored queue when it was read or written last,
+	// which tells the commands sent or queued by other processes sharing the storage since then.
+	queueBase map[UUID]struct{}
+	tempIDs   TempIDMapping
+	// location is the timezone of the account, which is time.Local until the user is synced.
+	location *time.Location
+	// mu guards the state, caches, queue, temp ids and location.
+	mu sync.RWMutex
+	// syncMu serializes requests to the sync endpoint, so that responses are applied in order.
+	syncMu sync.Mutex
 }
 
+// NewClient returns a client which caches the state in files of the cache directory.
 func NewClient(endpoint, token, sync_token, cache_dir string, logger *log.Logger) (*Client, error) {
+	if len(cache_dir) == 0 {
+		cache_dir = "$HOME/.go-todoist"
+	}
+	cache_dir = os.ExpandEnv(cache_dir)
+	storage, err := NewFileStorage(cache_dir)
+	if err != nil {
+		return nil, err
+	}
+	if err = MigrateLegacyCache(cache_dir, token, storage); err != nil {
+		return nil, err
+	}
+	c, err := NewClientWithStorage(endpoint, token, sync_token, storage, logger)
+	if err != nil {
+		return nil, err
+	}
+	c.CacheDir = cache_dir
+	return c, nil
+}
+
+// NewClientWithStorage returns a client which caches the state in the storage.
+// The state is not persisted when the storage is nil.
+func NewClientWithStorage(endpoint, token, sync_token string, storage Storage, logger *log.Logger) (*Client, error) {
 	if len(endpoint) == 0 {
 		endpoint = "https://api.todoist.com/sync/v8"
 	}
@@ -51,16 +101,6 @@ func NewClient(endpoint, token, sync_token, cache_dir string, logger *log.Logger
 		sync_token = "*"
 	}
 
-	if len(cache_dir) == 0 {
-		cache_dir = "$HOME/.go-todoist"
-	}
-	cache_dir = os.ExpandEnv(cache_dir)
-	if _, err = os.Stat(cache_dir); err != nil {
-		if err = os.MkdirAll(cache_dir, 0755); err != nil {
-			return nil, err
-		}
-	}
-
 	if logger == nil {
 		logger = log.New(ioutil.Discard, "", log.LstdFlags)
 	}
@@ -68,22 +108,42 @@ func NewClient(endpoint, token, sync_token, cache_dir string, logger *log.Logger
 	c := &Client{
 		URL:        parsed_endpoint,
 		HTTPClient: client,
+		Retry:      DefaultRetryPolicy,
+		BatchSize:  DefaultBatchSize,
 		Token:      token,
 		SyncToken:  sync_token,
-		CacheDir:   cache_dir,
+		Storage:    storage,
 		syncState:  &SyncState{},
 		Logger:     logger,
+		tempIDs:    TempIDMapping{},
+		location:   time.Local,
 	}
+	c.Completed = &CompletedClient{c}
+	c.Filter = &FilterClient{c, newFilterCache()}
+	c.Item = &ItemClient{c, newItemCache()}
+	c.Label = &LabelClient{c, newLabelCache()}
+	c.Project = &ProjectClient{c, newProjectCache()}
+	c.Relation = &RelationClient{c}
+	c.Note = &NoteClient{c, newNoteCache()}
+	c.Reminder = &ReminderClient{c, newReminderCache()}
+	c.Section = &SectionClient{c, newSectionCache()}
+	c.Collaborator = &CollaboratorClient{c, newCollaboratorCache()}
+	c.Notification = &NotificationClient{c, newNotificationCache()}
+	c.User = &UserClient{c}
+	unlock, err := c.lockStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	if err = c.readCache(); err != nil {
+		if err != ErrNotFound {
+			c.Logger.Printf("failed to read the cache: %s", err)
+		}
 		c.resetState()
 	}
-	c.Completed = &CompletedClient{c}
-	c.Filter = &FilterClient{c, &filterCache{&c.syncState.Filters}}
-	c.Item = &ItemClient{c, &itemCache{&c.syncState.Items}}
-	c.Label = &LabelClient{c, &labelCache{&c.syncState.Labels}}
-	c.Project = &ProjectClient{c, &projectCache{&c.syncState.Projects}}
-	c.Relation = &RelationClient{c}
-	c.Note = &NoteClient{c, &noteCache{&c.syncState.Notes}}
+	if err = c.readQueue(); err != nil {
+		c.Logger.Printf("failed to read the queue: %s", err)
+	}
 	return c, nil
 }
 
@@ -119,20 +179,75 @@ func (c *Client) newSyncRequest(ctx context.Context, values url.Values) (*http.R
 	return c.newRequest(ctx, http.MethodPost, "sync", values)
 }
 
+// do sends the request with retries according to the retry policy.
+// Sync commands are safe to retry because the server ignores the commands with the same uuid.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.WithContext(ctx)
+			r.Body = body
+		}
+		res, err := c.HTTPClient.Do(r)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt >= c.Retry.MaxRetries || !shouldRetry(ctx, res, err) {
+			return res, err
+		}
+		wait := c.Retry.backoff(attempt)
+		if err != nil {
+			c.Logger.Printf("request failed: %s, retry after %s", err, wait)
+		} else {
+			if d, ok := retryAfter(res); ok {
+				wait = d
+			}
+			c.Logger.Printf("request failed with status code %d, retry after %s", res.StatusCode, wait)
+			ioutil.ReadAll(res.Body)
+			res.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
 func decodeBody(resp *http.Response, out interface{}) error {
 	defer resp.Body.Close()
 	decoder := json.NewDecoder(resp.Body)
 	return decoder.Decode(out)
 }
 
+// Sync sends the commands and retrieves the changes since the last sync token.
+// The changes are merged into the cached state.
 func (c *Client) Sync(ctx context.Context, commands []Command) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	return c.sync(ctx, commands)
+}
+
+// sync requires syncMu to be held, so that responses are applied in order.
+func (c *Client) sync(ctx context.Context, commands []Command) error {
 	b, err := json.Marshal(commands)
 	if err != nil {
 		return err
 	}
+	c.mu.RLock()
+	syncToken := c.SyncToken
+	dayOrdersTimestamp := c.syncState.DayOrdersTimestamp
+	c.mu.RUnlock()
 	values := url.Values{
-		"sync_token":           {c.SyncToken},
-		"day_orders_timestamp": {""},
+		"sync_token":           {syncToken},
+		"day_orders_timestamp": {dayOrdersTimestamp},
 		"resource_types":       {"[\"all\"]"},
 		"commands":             {string(b)},
 	}
@@ -141,45 +256,237 @@ func (c *Client) Sync(ctx context.Context, commands []Command) error {
 		return err
 	}
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return err
 	}
 	if (res.StatusCode / 100) != 2 {
-		return fmt.Errorf("failed to sync, status code: %d, command: %v", res.StatusCode, commands)
+		res.Body.Close()
+		return &StatusError{Op: "sync", StatusCode: res.StatusCode}
 	}
 	var out SyncState
 	err = decodeBody(res, &out)
 	if err != nil {
 		return err
 	}
-	// TODO: replace temp_id mapping
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err = c.replaceTempIDs(out.TempIDMapping); err != nil {
+		return err
+	}
 	c.updateState(&out)
+	syncErr := newSyncError(commands, out.SyncStatus)
+	if e, ok := syncErr.(*SyncError); ok {
+		for _, ce := range e.Errors {
+			if !ce.Command.TempID.IsZero() {
+				c.discardTempID(ce.Command.TempID)
+			}
+		}
+	}
 	c.writeCache()
-	return nil
+	return syncErr
 }
 
+// FullSync discards the cached state and retrieves all the resources.
 func (c *Client) FullSync(ctx context.Context, commands []Command) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	c.mu.Lock()
 	c.resetState()
-	return c.Sync(ctx, commands)
+	c.mu.Unlock()
+	return c.sync(ctx, commands)
 }
 
+// Commit sends the queued commands.
+// The queue is split into batches of BatchSize commands, which are sent in order.
+// Temp ids created in earlier batches are resolved in later ones.
+// Command errors of all the batches are aggregated into a SyncError.
+// If a batch fails to be sent by a temporary error (see IsTemporary) or the context is done,
+// it and the following batches remain in the queue.
+// If it is rejected by the other errors, it fails again, so it is removed from the queue
+// with the resources added by it, and the following batches remain.
 func (c *Client) Commit(ctx context.Context) error {
-	if len(c.queue) == 0 {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	var errs []CommandError
+	for {
+		c.mu.Lock()
+		if len(c.queue) == 0 {
+			if unlock, err := c.lockStorage(); err == nil {
+				c.writeQueue()
+				unlock()
+			}
+			c.mu.Unlock()
+			break
+		}
+		n := c.BatchSize
+		if n <= 0 || n > len(c.queue) {
+			n = len(c.queue)
+		}
+		batch := c.queue[:n:n]
+		c.queue = c.queue[n:]
+		c.mu.Unlock()
+
+		err := c.sync(ctx, batch)
+		if e, ok := err.(*SyncError); ok {
+			errs = append(errs, e.Errors...)
+			continue
+		}
+		if err != nil {
+			c.mu.Lock()
+			if IsTemporary(err) || ctx.Err() != nil {
+				// keep unsent commands, and the resources added by them, for replay.
+				c.queue = append(append([]Command{}, batch...), c.queue...)
+			} else {
+				for _, command := range batch {
+					if !command.TempID.IsZero() {
+						c.discardTempID(command.TempID)
+					}
+				}
+			}
+			c.writeCache()
+			c.mu.Unlock()
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return &SyncError{Errors: errs}
+	}
+	return nil
+}
+
+// TempIDMapping returns all the temporary ids resolved by the server since the client was created.
+func (c *Client) TempIDMapping() TempIDMapping {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := TempIDMapping{}
+	for k, v := range c.tempIDs {
+		res[k] = v
+	}
+	return res
+}
+
+// ResolveID returns the real id when the given id is a resolved temporary id.
+func (c *Client) ResolveID(id ID) ID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tempIDs.Resolve(id)
+}
+
+func (c *Client) enqueue(command Command) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queue = append(c.queue, command)
+}
+
+// replaceTempIDs re-keys cached resources created with temporary ids,
+// and rewrites the references in pending commands.
+func (c *Client) replaceTempIDs(mapping TempIDMapping) error {
+	if len(mapping) == 0 {
 		return nil
 	}
-	err := c.Sync(ctx, c.queue)
-	c.queue = []Command{}
-	return err
+	for k, v := range mapping {
+		c.tempIDs[k] = v
+	}
+	c.Filter.cache.replaceTempIDs(mapping)
+	c.Item.cache.replaceTempIDs(mapping)
+	c.Label.cache.replaceTempIDs(mapping)
+	c.Project.cache.replaceTempIDs(mapping)
+	c.Note.cache.replaceTempIDs(mapping)
+	c.Reminder.cache.replaceTempIDs(mapping)
+	c.Section.cache.replaceTempIDs(mapping)
+	c.Collaborator.cache.replaceTempIDs(mapping)
+	// live notifications are created by the server, so they never refer to temporary ids.
+	for i, command := range c.queue {
+		args, err := mapping.replaceArgs(command.Args)
+		if err != nil {
+			return err
+		}
+		c.queue[i].Args = args
+	}
+	return nil
+}
+
+// discardTempID removes a cached resource which was added with the rejected command.
+func (c *Client) discardTempID(id ID) {
+	entity := Entity{ID: id}
+	c.Filter.cache.remove(Filter{Entity: entity})
+	c.Item.cache.remove(Item{Entity: entity})
+	c.Label.cache.remove(Label{Entity: entity})
+	c.Project.cache.remove(Project{Entity: entity})
+	c.Note.cache.remove(Note{Entity: entity})
+	c.Reminder.cache.remove(Reminder{Entity: entity})
+	c.Section.cache.remove(Section{Entity: entity})
+}
+
+// Location returns the timezone of the account, which is time.Local until the user is synced.
+// Full-day and floating dates of the cached resources are in it.
+func (c *Client) Location() *time.Location {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.location
 }
 
 func (c *Client) ResetSyncToken() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.SyncToken = "*"
 }
 
 func (c *Client) resetState() {
 	c.SyncToken = "*"
 	c.syncState = &SyncState{}
+	c.resetCaches()
+}
+
+func (c *Client) resetCaches() {
+	c.Filter.cache.reset()
+	c.Item.cache.reset()
+	c.Label.cache.reset()
+	c.Project.cache.reset()
+	c.Note.cache.reset()
+	c.Reminder.cache.reset()
+	c.Section.cache.reset()
+	c.Collaborator.cache.reset()
+	c.Notification.cache.reset()
+}
+
+// localizeCaches moves the full-day and floating due dates of the cached resources to the location,
+// when the timezone of the account is changed.
+func (c *Client) localizeCaches() {
+	for _, item := range c.Item.cache.getAll() {
+		if !item.Due.Date.IsZero() {
+			item.Due.Date = item.Due.Date.localize(c.location)
+			c.Item.cache.store(item)
+		}
+	}
+	for _, reminder := range c.Reminder.cache.getAll() {
+		if reminder.Due != nil {
+			due := *reminder.Due
+			due.Date = due.Date.localize(c.location)
+			reminder.Due = &due
+			c.Reminder.cache.store(reminder)
+		}
+	}
+}
+
+// snapshot returns the whole state including the cached resources.
+func (c *Client) snapshot() SyncState {
+	state := *c.syncState
+	state.Filters = c.Filter.cache.getAll()
+	state.Items = c.Item.cache.getAll()
+	state.Labels = c.Label.cache.getAll()
+	state.Projects = c.Project.cache.getAll()
+	state.Notes = c.Note.cache.getAll()
+	state.Reminders = c.Reminder.cache.getAll()
+	state.Sections = c.Section.cache.getAll()
+	state.Collaborators = c.Collaborator.cache.getAll()
+	state.CollaboratorStates = c.Collaborator.cache.getAllStates()
+	state.LiveNotifications = c.Notification.cache.getAll()
+	state.ProjectNotes = nil
+	state.TempIDMapping = nil
+	state.SyncStatus = nil
+	return state
 }
 
 func (c *Client) updateState(state *SyncState) {
@@ -187,17 +494,26 @@ func (c *Client) updateState(state *SyncState) {
 		c.SyncToken = state.SyncToken
 	}
 	/* TODO:
-	- day_orders
-	- day_orders_timestamp
-	- live_notifications_last_read_id
 	- locations
 	- settings_notifications
-	- user
 	*/
+	if state.User != nil {
+		user := *state.User
+		c.syncState.User = &user
+		if loc := user.Location(); loc.String() != c.location.String() {
+			c.location = loc
+			c.localizeCaches()
+		}
+	}
+	if state.FullSync {
+		// full sync returns all the resources without deleted ones.
+		c.resetCaches()
+	}
 	for _, filter := range state.Filters {
 		c.Filter.cache.store(filter)
 	}
 	for _, item := range state.Items {
+		item.Due.Date = item.Due.Date.localize(c.location)
 		c.Item.cache.store(item)
 	}
 	for _, label := range state.Labels {
@@ -212,38 +528,108 @@ func (c *Client) updateState(state *SyncState) {
 	for _, note := range state.ProjectNotes {
 		c.Note.cache.store(note)
 	}
-	c.syncState = state
+	for _, reminder := range state.Reminders {
+		if reminder.Due != nil {
+			due := *reminder.Due
+			due.Date = due.Date.localize(c.location)
+			reminder.Due = &due
+		}
+		c.Reminder.cache.store(reminder)
+	}
+	for _, section := range state.Sections {
+		c.Section.cache.store(section)
+	}
+	for _, collaborator := range state.Collaborators {
+		c.Collaborator.cache.store(collaborator)
+	}
+	for _, collaboratorState := range state.CollaboratorStates {
+		c.Collaborator.cache.storeState(collaboratorState)
+	}
+	for _, notification := range state.LiveNotifications {
+		c.Notification.cache.store(notification)
+	}
+	// day orders are returned only when they are changed since the timestamp.
+	if state.DayOrders != nil {
+		c.syncState.DayOrders = state.DayOrders
+	}
+	if len(state.DayOrdersTimestamp) != 0 {
+		c.syncState.DayOrdersTimestamp = state.DayOrdersTimestamp
+	}
+	if !state.LiveNotificationsLastReadID.IsZero() {
+		c.syncState.LiveNotificationsLastReadID = state.LiveNotificationsLastReadID
+	}
+	c.syncState.SyncToken = c.SyncToken
+	c.syncState.FullSync = state.FullSync
 }
 
+// storageKeyPrefix hashes the token, so that keys and file names do not leak it.
+func storageKeyPrefix(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:16])
+}
+
+// isClientKey reports whether the key is one of the keys of a client in the storage.
+func isClientKey(key string) bool {
+	ext := path.Ext(key)
+	if ext != ".json" && ext != ".sync" && ext != ".queue" {
+		return false
+	}
+	_, err := hex.DecodeString(strings.TrimSuffix(key, ext))
+	return err == nil && len(key)-len(ext) == len(storageKeyPrefix(""))
+}
+
+// keys in the storage
+func (c *Client) stateKey() string     { return storageKeyPrefix(c.Token) + ".json" }
+func (c *Client) syncTokenKey() string { return storageKeyPrefix(c.Token) + ".sync" }
+func (c *Client) queueKey() string     { return storageKeyPrefix(c.Token) + ".queue" }
+
 func (c *Client) readCache() error {
-	b, err := ioutil.ReadFile(path.Join(c.CacheDir, c.Token+".json"))
+	if c.Storage == nil {
+		return ErrNotFound
+	}
+	b, err := c.Storage.Load(c.stateKey())
 	if err != nil {
 		return err
 	}
-	if err = json.Unmarshal(b, c.syncState); err != nil {
+	state, err := decodeCache(b)
+	if err != nil {
 		return err
 	}
-	b, err = ioutil.ReadFile(path.Join(c.CacheDir, c.Token+".sync"))
+	b, err = c.Storage.Load(c.syncTokenKey())
 	if err != nil {
 		return err
 	}
-	c.SyncToken = string(b)
+	// the cache holds all the resources like a response of full sync.
+	state.SyncToken = string(b)
+	state.FullSync = true
+	c.updateState(state)
 	return nil
 }
 
+// lockStorage locks the storage across processes if it supports locking.
+func (c *Client) lockStorage() (func() error, error) {
+	return lockStorage(c.Storage)
+}
+
 func (c *Client) writeCache() error {
-	if len(c.CacheDir) == 0 {
+	if c.Storage == nil {
 		return nil
 	}
-	b, err := json.MarshalIndent(c.syncState, "", "  ")
+	unlock, err := c.lockStorage()
 	if err != nil {
 		return err
 	}
-	if err = ioutil.WriteFile(path.Join(c.CacheDir, c.Token+".json"), b, 0644); err != nil {
+	defer unlock()
+	// the queue is merged first, which may discard resources added by commands sent by other processes.
+	if err = c.writeQueue(); err != nil {
+		return err
+	}
+	b, err := encodeCache(c.snapshot())
+	if err != nil {
 		return err
 	}
-	if err = ioutil.WriteFile(path.Join(c.CacheDir, c.Token+".sync"), []byte(c.SyncToken), 0644); err != nil {
+	if err = c.Storage.Save(c.stateKey(), b); err != nil {
 		return err
 	}
-	return nil
+	return c.Storage.Save(c.syncTokenKey(), []byte(c.SyncToken))
 }
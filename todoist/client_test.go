@@ -0,0 +1,310 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestClient(t testing.TB, endpoint string) *Client {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(endpoint, "test-token", "*", dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClient_updateState(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	item := func(id, content string) Item {
+		i := Item{Content: content}
+		i.ID = ID(id)
+		return i
+	}
+	c.updateState(&SyncState{
+		SyncToken: "token1",
+		FullSync:  true,
+		Items:     []Item{item("1", "foo"), item("2", "bar")},
+	})
+
+	deleted := item("2", "bar")
+	deleted.IsDeleted = true
+	c.updateState(&SyncState{
+		SyncToken: "token2",
+		Items:     []Item{item("1", "baz"), deleted, item("3", "qux")},
+	})
+	if c.SyncToken != "token2" {
+		t.Errorf("Expect %s, but got %s", "token2", c.SyncToken)
+	}
+	items := c.Item.GetAll()
+	if len(items) != 2 || items[0].Content != "baz" || items[1].Content != "qux" {
+		t.Errorf("Unexpect items: %#v", items)
+	}
+	if len(c.snapshot().Items) != 2 {
+		t.Errorf("Expect %d, but got %d", 2, len(c.snapshot().Items))
+	}
+
+	c.updateState(&SyncState{
+		SyncToken: "token3",
+		FullSync:  true,
+		Items:     []Item{item("4", "quux")},
+	})
+	items = c.Item.GetAll()
+	if len(items) != 1 || items[0].ID != "4" {
+		t.Errorf("Unexpect items: %#v", items)
+	}
+}
+
+func TestClient_SyncRetry(t *testing.T) {
+	var count int
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		bodies = append(bodies, r.FormValue("commands"))
+		switch count {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, `{"sync_token": "token1", "full_sync": true}`)
+		}
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+	c.Retry = RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	if err := c.Sync(context.Background(), []Command{{Type: "item_close", UUID: GenerateUUID()}}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if count != 3 {
+		t.Errorf("Expect %d, but got %d", 3, count)
+	}
+	if bodies[0] != bodies[1] || bodies[1] != bodies[2] {
+		t.Errorf("Expect same commands, but got %v", bodies)
+	}
+	if c.SyncToken != "token1" {
+		t.Errorf("Expect %s, but got %s", "token1", c.SyncToken)
+	}
+}
+
+func TestClient_SyncRetryExhausted(t *testing.T) {
+	var count int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+	c.Retry = RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	if err := c.Sync(context.Background(), []Command{}); err == nil {
+		t.Error("Expect error, but no error")
+	}
+	if count != 3 {
+		t.Errorf("Expect %d, but got %d", 3, count)
+	}
+}
+
+func TestClient_SyncRetryContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := c.Sync(ctx, []Command{}); err != context.DeadlineExceeded {
+		t.Errorf("Expect %s, but got %v", context.DeadlineExceeded, err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Expect to be bounded by the context, but took %s", d)
+	}
+}
+
+func TestClient_CommitRejected(t *testing.T) {
+	var count int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+	c.Retry = RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	item, _ := NewItem("foo", &NewItemOpts{ProjectID: ID("1000000")})
+	c.Item.Add(*item)
+	err := c.Commit(context.Background())
+	if e, ok := err.(*StatusError); !ok || e.StatusCode != http.StatusBadRequest || IsTemporary(err) {
+		t.Fatalf("Expect permanent status error, but got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expect no retries, but sent %d times", count)
+	}
+	// the rejected batch is not sent again.
+	if n := len(c.Queue()); n != 0 {
+		t.Errorf("Expect %d, but got %d", 0, n)
+	}
+	if i := c.Item.Resolve(item.ID); i != nil {
+		t.Errorf("Expect nil, but got %v", i)
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if !shouldRetry(ctx, nil, errors.New("connection refused")) {
+		t.Error("Expect to retry network errors")
+	}
+	if shouldRetry(ctx, &http.Response{StatusCode: http.StatusUnauthorized}, nil) {
+		t.Error("Expect not to retry 401")
+	}
+	cancel()
+	if shouldRetry(ctx, nil, context.Canceled) {
+		t.Error("Expect not to retry after the context is canceled")
+	}
+}
+
+func TestClient_CommitBatches(t *testing.T) {
+	var requests [][]map[string]interface{}
+	var lastID int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var commands []map[string]interface{}
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, commands)
+		mapping := map[string]int{}
+		status := map[string]interface{}{}
+		for _, command := range commands {
+			if tempID, ok := command["temp_id"].(string); ok {
+				lastID++
+				mapping[tempID] = lastID
+			}
+			status[command["uuid"].(string)] = "ok"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":      fmt.Sprintf("token%d", len(requests)),
+			"temp_id_mapping": mapping,
+			"sync_status":     status,
+		})
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+	c.BatchSize = 2
+
+	project, _ := NewProject("foo", &NewProjectOpts{})
+	c.Project.Add(*project)
+	for _, content := range []string{"bar", "baz"} {
+		item, _ := NewItem(content, &NewItemOpts{ProjectID: project.ID})
+		c.Item.Add(*item)
+	}
+	if err := c.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(requests) != 2 || len(requests[0]) != 2 || len(requests[1]) != 1 {
+		t.Fatalf("Unexpect batches: %v", requests)
+	}
+	args := requests[1][0]["args"].(map[string]interface{})
+	if args["project_id"] != float64(1) {
+		t.Errorf("Expect %v, but got %v", 1, args["project_id"])
+	}
+	if id := c.ResolveID(project.ID); id != "1" {
+		t.Errorf("Expect %s, but got %s", "1", id)
+	}
+	if p := c.Project.Resolve(project.ID); p == nil || p.ID != "1" {
+		t.Errorf("Expect project to be re-keyed, but got %v", p)
+	}
+	if items := c.Item.FindByProjectIDs([]ID{"1"}); len(items) != 2 {
+		t.Errorf("Expect %d, but got %d", 2, len(items))
+	}
+}
+
+func TestClient_Concurrency(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string]int{}
+	var lastID int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		mapping := map[ID]int{}
+		for _, command := range commands {
+			sent[string(command.UUID)]++
+			lastID++
+			mapping[command.TempID] = lastID
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":      fmt.Sprintf("token%d", lastID),
+			"temp_id_mapping": mapping,
+		})
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+	c.BatchSize = 7
+
+	const workers, items = 8, 20
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < items; i++ {
+				item, _ := NewItem(fmt.Sprintf("item %d-%d", w, i), &NewItemOpts{})
+				c.Item.Add(*item)
+				c.Item.GetAll()
+				c.Item.Resolve(item.ID)
+				c.Project.GetAll()
+				if i%5 == 0 {
+					if err := c.Commit(context.Background()); err != nil {
+						t.Errorf("Unexpect error: %s", err)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := c.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(sent) != workers*items {
+		t.Errorf("Expect %d, but got %d", workers*items, len(sent))
+	}
+	for uuid, n := range sent {
+		if n != 1 {
+			t.Errorf("Expect command %s to be sent once, but sent %d times", uuid, n)
+		}
+	}
+	if n := len(c.Item.GetAll()); n != workers*items {
+		t.Errorf("Expect %d, but got %d", workers*items, n)
+	}
+	if n := len(c.Queue()); n != 0 {
+		t.Errorf("Expect %d, but got %d", 0, n)
+	}
+}
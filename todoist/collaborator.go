@@ -0,0 +1,251 @@
+package todoist
+
+type Collaborator struct {
+	Entity
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+	Timezone string `json:"timezone"`
+	ImageID  string `json:"image_id"`
+}
+
+func (c Collaborator) String() string {
+	if len(c.FullName) == 0 {
+		return c.Email
+	}
+	return c.FullName
+}
+
+func (c Collaborator) ColorString() string {
+	return c.String()
+}
+
+// CollaboratorState is the state of a collaborator in a shared project.
+type CollaboratorState struct {
+	ProjectID ID      `json:"project_id"`
+	UserID    ID      `json:"user_id"`
+	State     string  `json:"state"`
+	IsDeleted IntBool `json:"is_deleted"`
+}
+
+const (
+	CollaboratorStateActive  = "active"
+	CollaboratorStateInvited = "invited"
+)
+
+func (s CollaboratorState) key() ID {
+	return s.ProjectID + ":" + s.UserID
+}
+
+type CollaboratorClient struct {
+	*Client
+	cache *collaboratorCache
+}
+
+// Delete removes the collaborator of the email from the shared project.
+func (c CollaboratorClient) Delete(projectID ID, email string) error {
+	command := Command{
+		Type: "delete_collaborator",
+		UUID: GenerateUUID(),
+		Args: map[string]interface{}{
+			"project_id": projectID,
+			"email":      email,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c CollaboratorClient) AcceptInvitation(id ID, secret string) error {
+	command := Command{
+		Type: "accept_invitation",
+		UUID: GenerateUUID(),
+		Args: map[string]interface{}{
+			"invitation_id":     id,
+			"invitation_secret": secret,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c CollaboratorClient) RejectInvitation(id ID, secret string) error {
+	command := Command{
+		Type: "reject_invitation",
+		UUID: GenerateUUID(),
+		Args: map[string]interface{}{
+			"invitation_id":     id,
+			"invitation_secret": secret,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c CollaboratorClient) GetAll() []Collaborator {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.getAll()
+}
+
+func (c CollaboratorClient) Resolve(id ID) *Collaborator {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(id)
+}
+
+// ResolveUser returns the collaborator of the id.
+// The user of the account is also resolved even if no project is shared.
+func (c CollaboratorClient) ResolveUser(id ID) *Collaborator {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if collaborator := c.cache.resolve(id); collaborator != nil {
+		return collaborator
+	}
+	if user := c.syncState.User; user != nil && user.ID == id {
+		collaborator := Collaborator{
+			Email:    user.Email,
+			FullName: user.FullName,
+			Timezone: user.TzInfo.Timezone,
+			ImageID:  user.ImageID,
+		}
+		collaborator.ID = user.ID
+		return &collaborator
+	}
+	return nil
+}
+
+func (c CollaboratorClient) FindOneByEmail(email string) *Collaborator {
+	for _, collaborator := range c.GetAll() {
+		if collaborator.Email == email {
+			return &collaborator
+		}
+	}
+	return nil
+}
+
+// FindByProjectID returns the active collaborators of the project.
+func (c CollaboratorClient) FindByProjectID(id ID) []Collaborator {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []Collaborator
+	for _, state := range c.cache.findStates(id) {
+		if state.State != CollaboratorStateActive {
+			continue
+		}
+		if collaborator := c.cache.resolve(state.UserID); collaborator != nil {
+			res = append(res, *collaborator)
+		}
+	}
+	return res
+}
+
+// GetStates returns the states of the collaborators of the project.
+func (c CollaboratorClient) GetStates(projectID ID) []CollaboratorState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.findStates(projectID)
+}
+
+type collaboratorCache struct {
+	collaborators map[ID]Collaborator
+	order         *orderedIDs
+	states        map[ID]CollaboratorState
+	stateOrder    *orderedIDs
+	byProject     idIndex
+}
+
+func newCollaboratorCache() *collaboratorCache {
+	c := &collaboratorCache{}
+	c.reset()
+	return c
+}
+
+func (c *collaboratorCache) reset() {
+	c.collaborators = map[ID]Collaborator{}
+	c.order = newOrderedIDs()
+	c.states = map[ID]CollaboratorState{}
+	c.stateOrder = newOrderedIDs()
+	c.byProject = idIndex{}
+}
+
+func (c *collaboratorCache) getAll() []Collaborator {
+	res := make([]Collaborator, 0, len(c.collaborators))
+	for _, id := range c.order.list() {
+		res = append(res, c.collaborators[id])
+	}
+	return res
+}
+
+func (c *collaboratorCache) getAllStates() []CollaboratorState {
+	res := make([]CollaboratorState, 0, len(c.states))
+	for _, key := range c.stateOrder.list() {
+		res = append(res, c.states[key])
+	}
+	return res
+}
+
+func (c *collaboratorCache) resolve(id ID) *Collaborator {
+	if collaborator, ok := c.collaborators[id]; ok {
+		return &collaborator
+	}
+	return nil
+}
+
+// findStates returns the states of the project in insertion order.
+func (c *collaboratorCache) findStates(projectID ID) []CollaboratorState {
+	keys := append([]ID{}, c.byProject.get(projectID)...)
+	c.stateOrder.sort(keys)
+	var res []CollaboratorState
+	for _, key := range keys {
+		res = append(res, c.states[key])
+	}
+	return res
+}
+
+func (c *collaboratorCache) store(collaborator Collaborator) {
+	if collaborator.IsDeleted.Bool() {
+		c.remove(collaborator)
+		return
+	}
+	c.collaborators[collaborator.ID] = collaborator
+	c.order.add(collaborator.ID)
+}
+
+func (c *collaboratorCache) remove(collaborator Collaborator) {
+	if _, ok := c.collaborators[collaborator.ID]; !ok {
+		return
+	}
+	delete(c.collaborators, collaborator.ID)
+	c.order.remove(collaborator.ID)
+}
+
+func (c *collaboratorCache) storeState(state CollaboratorState) {
+	key := state.key()
+	if state.IsDeleted.Bool() {
+		if _, ok := c.states[key]; ok {
+			delete(c.states, key)
+			c.stateOrder.remove(key)
+			c.byProject.remove(state.ProjectID, key)
+		}
+		return
+	}
+	c.states[key] = state
+	c.stateOrder.add(key)
+	c.byProject.add(state.ProjectID, key)
+}
+
+func (c *collaboratorCache) replaceTempIDs(mapping TempIDMapping) {
+	// collaborators are users, and only the states refer to projects which may be added with temporary ids.
+	for tempID := range mapping {
+		for _, key := range c.byProject.get(tempID) {
+			old := c.states[key]
+			s := old
+			s.ProjectID = mapping.Resolve(s.ProjectID)
+			delete(c.states, old.key())
+			c.byProject.remove(old.ProjectID, old.key())
+			c.stateOrder.rename(old.key(), s.key())
+			c.states[s.key()] = s
+			c.byProject.add(s.ProjectID, s.key())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package todoist
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestCollaboratorClient(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	collaborator := func(id, email string) Collaborator {
+		col := Collaborator{Email: email}
+		col.ID = ID(id)
+		return col
+	}
+	c.updateState(&SyncState{
+		FullSync:      true,
+		User:          &User{ID: "1", Email: "me@example.com"},
+		Collaborators: []Collaborator{collaborator("2", "foo@example.com"), collaborator("3", "bar@example.com")},
+		CollaboratorStates: []CollaboratorState{
+			{ProjectID: "10", UserID: "2", State: CollaboratorStateActive},
+			{ProjectID: "10", UserID: "3", State: CollaboratorStateInvited},
+			{ProjectID: "20", UserID: "3", State: CollaboratorStateActive},
+		},
+	})
+	if cs := c.Collaborator.FindByProjectID("10"); len(cs) != 1 || cs[0].ID != "2" {
+		t.Errorf("Unexpect collaborators: %v", cs)
+	}
+	c.updateState(&SyncState{
+		CollaboratorStates: []CollaboratorState{{ProjectID: "20", UserID: "3", IsDeleted: true}},
+	})
+	if cs := c.Collaborator.FindByProjectID("20"); len(cs) != 0 {
+		t.Errorf("Unexpect collaborators: %v", cs)
+	}
+	if col := c.Collaborator.FindOneByEmail("bar@example.com"); col == nil || col.ID != "3" {
+		t.Errorf("Unexpect collaborator: %v", col)
+	}
+
+	item := Item{ResponsibleUID: "2", AssignedByUID: "1"}
+	relations := c.Relation.Items([]Item{item})
+	if relations.Users["2"].Email != "foo@example.com" || relations.Users["1"].Email != "me@example.com" {
+		t.Errorf("Unexpect users: %v", relations.Users)
+	}
+
+	project, _ := NewProject("shared", &NewProjectOpts{})
+	c.Project.Add(*project)
+	c.Project.Share(project.ID, "baz@example.com")
+	c.updateState(&SyncState{
+		CollaboratorStates: []CollaboratorState{{ProjectID: project.ID, UserID: "2", State: CollaboratorStateActive}},
+	})
+	c.replaceTempIDs(TempIDMapping{project.ID: "30"})
+	if cs := c.Collaborator.FindByProjectID("30"); len(cs) != 1 {
+		t.Errorf("Unexpect collaborators: %v", cs)
+	}
+	if args := c.Queue()[1].Args.(map[string]interface{}); fmt.Sprint(args["project_id"]) != "30" {
+		t.Errorf("Expect %s, but got %v", "30", args["project_id"])
+	}
+}
+
+func TestCollaboratorClient_Invitation(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	if err := c.Collaborator.AcceptInvitation("1", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Collaborator.RejectInvitation("2", "def"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		typ    string
+		id     ID
+		secret string
+	}{
+		{"accept_invitation", "1", "abc"},
+		{"reject_invitation", "2", "def"},
+	}
+	queue := c.Queue()
+	if len(queue) != len(tests) {
+		t.Fatalf("expect %d commands, but got %d", len(tests), len(queue))
+	}
+	for i, test := range tests {
+		if queue[i].Type != test.typ {
+			t.Errorf("expect %s, but got %s", test.typ, queue[i].Type)
+		}
+		args := queue[i].Args.(map[string]interface{})
+		if args["invitation_id"] != test.id || args["invitation_secret"] != test.secret {
+			t.Errorf("unexpected args: %v", args)
+		}
+	}
+}
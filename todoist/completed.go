@@ -3,6 +3,7 @@ package todoist
 import (
 	"context"
 	"net/url"
+	"time"
 )
 
 type Stats struct {
@@ -80,11 +81,19 @@ type CompletedItems struct {
 	Projects map[ID]Project `json:"projects"`
 }
 
+// GroupByCompletedDate groups the items by the dates they are completed on in the local timezone.
+//
+// Deprecated: Use GroupByCompletedDateIn with Client.Location to group them in the timezone of the account.
 func (c *CompletedItems) GroupByCompletedDate() map[string][]Item {
+	return c.GroupByCompletedDateIn(time.Local)
+}
+
+// GroupByCompletedDateIn groups the items by the dates they are completed on in the timezone.
+func (c *CompletedItems) GroupByCompletedDateIn(loc *time.Location) map[string][]Item {
 	const layout = "2006-01-02"
 	res := map[string][]Item{}
 	for _, item := range c.Items {
-		date := item.CompletedDate.Local().Format(layout)
+		date := item.CompletedDate.In(loc).Format(layout)
 		res[date] = append(res[date], item)
 	}
 	return res
@@ -99,7 +108,7 @@ func (c *CompletedClient) GetStats() (*Stats, error) {
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -113,11 +122,15 @@ func (c *CompletedClient) GetAll() (*CompletedItems, error) {
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	var out CompletedItems
 	decodeBody(res, &out)
+	loc := c.Location()
+	for i := range out.Items {
+		out.Items[i].Due.Date = out.Items[i].Due.Date.localize(loc)
+	}
 	return &out, nil
 }
@@ -0,0 +1,176 @@
+package todoist
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"golang.org/x/crypto/pbkdf2"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	encryptionSaltKey  = "encryption.salt"
+	encryptionCheckKey = "encryption.check"
+	pbkdf2Iterations   = 100000
+)
+
+var encryptionCheckValue = []byte("go-todoist")
+
+// ErrInvalidKey is returned when cached values cannot be decrypted with the given key.
+var ErrInvalidKey = errors.New("invalid encryption key or passphrase")
+
+// EncryptedStorage encrypts values with AES-GCM before saving them to the underlying storage.
+type EncryptedStorage struct {
+	Storage Storage
+	aead    cipher.AEAD
+}
+
+// NewEncryptedStorage returns a storage encrypting values with the 32 bytes key.
+// It fails with ErrInvalidKey if the storage was encrypted with another key.
+// When the encryption is enabled on a storage with plaintext values, they are encrypted in place,
+// which requires the storage to be a Lister.
+func NewEncryptedStorage(storage Storage, key []byte) (*EncryptedStorage, error) {
+	unlock, err := lockStorage(storage)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return newEncryptedStorage(storage, key)
+}
+
+// NewPassphraseStorage returns a storage encrypting values with a key derived from the passphrase.
+// A random salt is kept in the underlying storage.
+func NewPassphraseStorage(storage Storage, passphrase string) (*EncryptedStorage, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+	unlock, err := lockStorage(storage)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	salt, err := storage.Load(encryptionSaltKey)
+	if err == ErrNotFound {
+		salt = make([]byte, 16)
+		if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+		err = storage.Save(encryptionSaltKey, salt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newEncryptedStorage(storage, pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, 32, sha256.New))
+}
+
+// newEncryptedStorage is NewEncryptedStorage with the storage locked.
+func newEncryptedStorage(storage Storage, key []byte) (*EncryptedStorage, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s := &EncryptedStorage{Storage: storage, aead: aead}
+	b, err := s.Load(encryptionCheckKey)
+	switch err {
+	case nil:
+		if !bytes.Equal(b, encryptionCheckValue) {
+			return nil, ErrInvalidKey
+		}
+	case ErrNotFound:
+		// the encryption is enabled just now, so the values in the storage are plaintext.
+		if err = s.encryptPlaintext(); err != nil {
+			return nil, err
+		}
+		if err = s.Save(encryptionCheckKey, encryptionCheckValue); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	return s, nil
+}
+
+// encryptPlaintext encrypts the values of clients saved before the encryption is enabled, such as the cache and the queue.
+// Otherwise they cannot be loaded, and the queued commands are lost.
+func (s *EncryptedStorage) encryptPlaintext() error {
+	lister, ok := s.Storage.(Lister)
+	if !ok {
+		return errors.New("cannot encrypt the existing cache because the storage does not list the keys, clear the cache to enable the encryption")
+	}
+	keys, err := lister.Keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		// the directory of the file storage may have other files such as the config.
+		if !isClientKey(key) {
+			continue
+		}
+		b, err := s.Storage.Load(key)
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err = s.Save(key, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadKeyFile derives a 32 bytes key from the content of the file.
+func ReadKeyFile(file string) ([]byte, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil, errors.New("key file is empty")
+	}
+	key := sha256.Sum256(b)
+	return key[:], nil
+}
+
+func (s *EncryptedStorage) Load(key string) ([]byte, error) {
+	b, err := s.Storage.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	size := s.aead.NonceSize()
+	if len(b) < size {
+		return nil, ErrInvalidKey
+	}
+	// the key is used as additional data so that values cannot be swapped.
+	v, err := s.aead.Open(nil, b[:size], b[size:], []byte(key))
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return v, nil
+}
+
+func (s *EncryptedStorage) Save(key string, value []byte) error {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	return s.Storage.Save(key, s.aead.Seal(nonce, nonce, value, []byte(key)))
+}
+
+func (s *EncryptedStorage) Delete(key string) error {
+	return s.Storage.Delete(key)
+}
+
+// Lock locks the underlying storage if it is a Locker.
+func (s *EncryptedStorage) Lock() (func() error, error) {
+	return lockStorage(s.Storage)
+}
@@ -0,0 +1,75 @@
+package todoist
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptedStorage(t *testing.T) {
+	memory := NewMemoryStorage()
+	s, err := NewPassphraseStorage(memory, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Save("foo", []byte("bar")); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := memory.Load("foo"); bytes.Contains(b, []byte("bar")) {
+		t.Errorf("Expect encrypted value, but got %s", string(b))
+	}
+	if b, err := s.Load("foo"); err != nil || string(b) != "bar" {
+		t.Errorf("Expect %s, but got %s (%v)", "bar", string(b), err)
+	}
+
+	s, err = NewPassphraseStorage(memory, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := s.Load("foo"); err != nil || string(b) != "bar" {
+		t.Errorf("Expect %s, but got %s (%v)", "bar", string(b), err)
+	}
+	if _, err = NewPassphraseStorage(memory, "wrong"); err != ErrInvalidKey {
+		t.Errorf("Expect %s, but got %v", ErrInvalidKey, err)
+	}
+}
+
+func TestEncryptedStorage_plaintext(t *testing.T) {
+	memory := NewMemoryStorage()
+	key := storageKeyPrefix("test-token") + ".queue"
+	memory.Save(key, []byte("queued"))
+	memory.Save("config.json", []byte("{}"))
+	s, err := NewPassphraseStorage(memory, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := memory.Load(key); bytes.Contains(b, []byte("queued")) {
+		t.Errorf("Expect encrypted value, but got %s", string(b))
+	}
+	if b, err := s.Load(key); err != nil || string(b) != "queued" {
+		t.Errorf("Expect %s, but got %s (%v)", "queued", string(b), err)
+	}
+	if b, _ := memory.Load("config.json"); string(b) != "{}" {
+		t.Errorf("Expect other values to be kept, but got %s", string(b))
+	}
+
+	// the values are encrypted only once
+	if s, err = NewPassphraseStorage(memory, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := s.Load(key); err != nil || string(b) != "queued" {
+		t.Errorf("Expect %s, but got %s (%v)", "queued", string(b), err)
+	}
+}
+
+// unlistableStorage hides Keys of MemoryStorage, so that it is not a Lister.
+type unlistableStorage struct {
+	*MemoryStorage
+}
+
+func (unlistableStorage) Keys() {}
+
+func TestEncryptedStorage_unlistable(t *testing.T) {
+	if _, err := NewEncryptedStorage(unlistableStorage{NewMemoryStorage()}, make([]byte, 32)); err == nil {
+		t.Error("Expect error, but got nil")
+	}
+}
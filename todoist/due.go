@@ -0,0 +1,402 @@
+package todoist
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDue parses the due date written in the natural language of lang, like "tomorrow 5pm",
+// "next fri", "in 3 days" or "Jan 27" in English, and "明日17時" or "来週金曜" in Japanese.
+// Recurring due dates like "every monday" are due on the first occurrence from today.
+// English is used if lang is empty. Dates are in the timezone loc, which is usually Client.Location.
+func ParseDue(value, lang string, loc *time.Location) (*Due, error) {
+	return parseDue(value, lang, time.Now().In(wallLocation(loc)))
+}
+
+// parseDue parses the due date relative to now, in the timezone of now.
+func parseDue(value, lang string, now time.Time) (*Due, error) {
+	if len(lang) == 0 {
+		lang = "en"
+	}
+	rules, ok := dueRules[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language of due date: %s", lang)
+	}
+	loc := wallLocation(now.Location())
+	s := dueState{now: now.In(loc)}
+	r, rest, err := parseRecurrence(normalizeDue(value), lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid due date %s: %s", value, err)
+	}
+	if err = s.parse(rest, rules); err != nil {
+		return nil, fmt.Errorf("invalid due date %s: %s", value, err)
+	}
+	if r != nil {
+		if s.hasDate {
+			return nil, fmt.Errorf("invalid due date %s: recurring due date with a date is not supported", value)
+		}
+		r.HasTime, r.Hour, r.Minute = s.hasTime, s.hour, s.minute
+		first := r.first(s.now)
+		if first.IsZero() {
+			return nil, fmt.Errorf("invalid due date %s: it never occurs", value)
+		}
+		due := Due{String: value, Lang: lang, IsRecurring: true}
+		due.Date, due.Timezone = dueDate(first.Time, r.HasTime, loc)
+		return &due, nil
+	}
+	if !s.hasDate && !s.hasTime {
+		return nil, fmt.Errorf("invalid due date: %s", value)
+	}
+	if !s.hasDate {
+		s.date = s.today()
+	}
+	due := Due{String: value, Lang: lang}
+	t := time.Date(s.date.Year(), s.date.Month(), s.date.Day(), s.hour, s.minute, 0, 0, loc)
+	due.Date, due.Timezone = dueDate(t, s.hasTime, loc)
+	return &due, nil
+}
+
+// dueDate returns the date and the timezone of the due date at the time.
+// Due dates with time are in UTC with the timezone, or floating if it is unknown.
+func dueDate(t time.Time, hasTime bool, loc *time.Location) (Time, string) {
+	if !hasTime || loc == time.Local {
+		return Time{t.In(loc)}, ""
+	}
+	return Time{t.UTC()}, loc.String()
+}
+
+func normalizeDue(value string) string {
+	return strings.ToLower(strings.TrimSpace(dueWidthReplacer.Replace(value)))
+}
+
+// parse applies the first matching rule to the head of the value repeatedly.
+func (s *dueState) parse(value string, rules []dueRule) error {
+	rest := value
+	for {
+		rest = strings.TrimLeft(rest, dueSeparators)
+		if len(rest) == 0 {
+			return nil
+		}
+		matched := false
+		for _, rule := range rules {
+			m := rule.pattern.FindStringSubmatch(rest)
+			if m == nil {
+				continue
+			}
+			if err := rule.apply(s, m); err != nil {
+				return err
+			}
+			rest = rest[len(m[0]):]
+			matched = true
+			break
+		}
+		if !matched {
+			return fmt.Errorf("unknown words %q", rest)
+		}
+	}
+}
+
+const dueSeparators = " ,、のに"
+
+// dueWidthReplacer replaces full-width digits and symbols used in Japanese with ascii ones.
+var dueWidthReplacer = strings.NewReplacer(
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+	"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"：", ":", "／", "/", "　", " ",
+)
+
+type dueState struct {
+	now     time.Time
+	date    time.Time
+	hasDate bool
+	hour    int
+	minute  int
+	hasTime bool
+}
+
+func (s *dueState) today() time.Time {
+	y, m, d := s.now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, s.now.Location())
+}
+
+func (s *dueState) setDate(date time.Time) error {
+	if s.hasDate {
+		return fmt.Errorf("date is given twice")
+	}
+	s.date = date
+	s.hasDate = true
+	return nil
+}
+
+func (s *dueState) setTime(hour, minute int) error {
+	if s.hasTime {
+		return fmt.Errorf("time is given twice")
+	}
+	if hour < 0 || 24 <= hour || minute < 0 || 60 <= minute {
+		return fmt.Errorf("no such time %02d:%02d", hour, minute)
+	}
+	s.hour, s.minute = hour, minute
+	s.hasTime = true
+	return nil
+}
+
+// setDay sets the date of the day. The next year is used if the year is not given and the day has passed.
+func (s *dueState) setDay(year int, month time.Month, day int) error {
+	today := s.today()
+	hasYear := year != 0
+	if !hasYear {
+		year = today.Year()
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Month() != month || date.Day() != day {
+		return fmt.Errorf("no such date %d-%02d-%02d", year, month, day)
+	}
+	if !hasYear && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return s.setDate(date)
+}
+
+// setWeekday sets the date of the weekday from today, or from tomorrow if strict.
+func (s *dueState) setWeekday(weekday time.Weekday, strict bool) error {
+	today := s.today()
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 && strict {
+		days = 7
+	}
+	return s.setDate(today.AddDate(0, 0, days))
+}
+
+// setAfter sets the date, and the time for units shorter than a day, after n units from now.
+func (s *dueState) setAfter(n int, unit string) error {
+	switch unit {
+	case "minute", "hour":
+		d := time.Duration(n) * time.Minute
+		if unit == "hour" {
+			d = time.Duration(n) * time.Hour
+		}
+		t := s.now.Add(d)
+		y, m, day := t.Date()
+		if err := s.setDate(time.Date(y, m, day, 0, 0, 0, 0, t.Location())); err != nil {
+			return err
+		}
+		return s.setTime(t.Hour(), t.Minute())
+	case "day":
+		return s.setDate(s.today().AddDate(0, 0, n))
+	case "week":
+		return s.setDate(s.today().AddDate(0, 0, 7*n))
+	case "month":
+		return s.setDate(s.today().AddDate(0, n, 0))
+	case "year":
+		return s.setDate(s.today().AddDate(n, 0, 0))
+	}
+	return fmt.Errorf("unknown unit %s", unit)
+}
+
+// setNext sets the first day of the next week, month or year. Weeks start on Monday.
+func (s *dueState) setNext(unit string) error {
+	today := s.today()
+	switch unit {
+	case "week":
+		return s.setWeekday(time.Monday, true)
+	case "month":
+		return s.setDate(time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()))
+	case "year":
+		return s.setDate(time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()))
+	}
+	return fmt.Errorf("unknown unit %s", unit)
+}
+
+type dueRule struct {
+	pattern *regexp.Regexp
+	apply   func(s *dueState, m []string) error
+}
+
+func newDueRule(pattern string, apply func(s *dueState, m []string) error) dueRule {
+	return dueRule{regexp.MustCompile("^(?:" + pattern + ")"), apply}
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+var (
+	englishWeekdays = map[string]time.Weekday{
+		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+		"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	}
+	englishMonths = map[string]time.Month{
+		"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+		"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+		"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+	}
+	japaneseWeekdays = map[string]time.Weekday{
+		"日": time.Sunday, "月": time.Monday, "火": time.Tuesday, "水": time.Wednesday,
+		"木": time.Thursday, "金": time.Friday, "土": time.Saturday,
+	}
+	japaneseUnits = map[string]string{
+		"分": "minute", "時間": "hour", "日": "day", "週間": "week",
+		"ヶ月": "month", "か月": "month", "ヵ月": "month", "カ月": "month", "年": "year",
+	}
+)
+
+const (
+	englishWeekdayPattern = `(sun|mon|tue|wed|thu|fri|sat)(?:day|sday|nesday|rsday|urday|s|r|rs)?`
+	englishMonthPattern   = `(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)(?:uary|ruary|ch|il|e|y|ust|t|tember|ober|ember)?\.?`
+)
+
+// dueCommonRules are the rules of the numeric formats used in any language.
+var dueCommonRules = []dueRule{
+	newDueRule(`(\d{4})[-/](\d{1,2})[-/](\d{1,2})`, func(s *dueState, m []string) error {
+		return s.setDay(atoi(m[1]), time.Month(atoi(m[2])), atoi(m[3]))
+	}),
+	newDueRule(`(\d{1,2}):(\d{2})`, func(s *dueState, m []string) error {
+		return s.setTime(atoi(m[1]), atoi(m[2]))
+	}),
+}
+
+var dueRules = map[string][]dueRule{
+	"en": append([]dueRule{
+		newDueRule(`(?:today|tod)\b`, func(s *dueState, m []string) error {
+			return s.setDate(s.today())
+		}),
+		newDueRule(`(?:tomorrow|tmr|tom)\b`, func(s *dueState, m []string) error {
+			return s.setDate(s.today().AddDate(0, 0, 1))
+		}),
+		newDueRule(`yesterday\b`, func(s *dueState, m []string) error {
+			return s.setDate(s.today().AddDate(0, 0, -1))
+		}),
+		newDueRule(`in\s+(\d+|an?)\s+(minute|min|hour|day|week|month|year)s?\b`, func(s *dueState, m []string) error {
+			n := 1
+			if m[1] != "a" && m[1] != "an" {
+				n = atoi(m[1])
+			}
+			unit := m[2]
+			if unit == "min" {
+				unit = "minute"
+			}
+			return s.setAfter(n, unit)
+		}),
+		newDueRule(`next\s+(week|month|year)\b`, func(s *dueState, m []string) error {
+			return s.setNext(m[1])
+		}),
+		newDueRule(`(next\s+)?`+englishWeekdayPattern+`\b`, func(s *dueState, m []string) error {
+			return s.setWeekday(englishWeekdays[m[2]], len(m[1]) != 0)
+		}),
+		newDueRule(englishMonthPattern+`\s*(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?\b`, func(s *dueState, m []string) error {
+			return s.setDay(atoi(m[3]), englishMonths[m[1]], atoi(m[2]))
+		}),
+		newDueRule(`(\d{1,2})(?:st|nd|rd|th)?\s+`+englishMonthPattern+`(?:,?\s+(\d{4}))?\b`, func(s *dueState, m []string) error {
+			return s.setDay(atoi(m[3]), englishMonths[m[2]], atoi(m[1]))
+		}),
+		newDueRule(`(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)\b`, func(s *dueState, m []string) error {
+			hour := atoi(m[1])
+			if hour < 1 || 12 < hour {
+				return fmt.Errorf("no such time %s", m[0])
+			}
+			hour %= 12
+			if m[3] == "pm" {
+				hour += 12
+			}
+			return s.setTime(hour, atoi(m[2]))
+		}),
+		newDueRule(`(?:at\s+)?noon\b`, func(s *dueState, m []string) error {
+			return s.setTime(12, 0)
+		}),
+		newDueRule(`(?:at\s+)?midnight\b`, func(s *dueState, m []string) error {
+			return s.setTime(0, 0)
+		}),
+		newDueRule(`at\s`, func(s *dueState, m []string) error {
+			return nil
+		}),
+	}, dueCommonRules...),
+	"ja": append([]dueRule{
+		newDueRule(`今日|きょう`, func(s *dueState, m []string) error {
+			return s.setDate(s.today())
+		}),
+		newDueRule(`明後日|あさって`, func(s *dueState, m []string) error {
+			return s.setDate(s.today().AddDate(0, 0, 2))
+		}),
+		newDueRule(`明日|あした`, func(s *dueState, m []string) error {
+			return s.setDate(s.today().AddDate(0, 0, 1))
+		}),
+		newDueRule(`昨日|きのう`, func(s *dueState, m []string) error {
+			return s.setDate(s.today().AddDate(0, 0, -1))
+		}),
+		newDueRule(`(\d+)\s*(分|時間|日|週間|ヶ月|か月|ヵ月|カ月|年)後`, func(s *dueState, m []string) error {
+			return s.setAfter(atoi(m[1]), japaneseUnits[m[2]])
+		}),
+		newDueRule(`来週の?([日月火水木金土])曜日?`, func(s *dueState, m []string) error {
+			// the weekday of the next week which starts on Monday.
+			today := s.today()
+			monday := today.AddDate(0, 0, 7-(int(today.Weekday())+6)%7)
+			days := (int(japaneseWeekdays[m[1]]) + 6) % 7
+			return s.setDate(monday.AddDate(0, 0, days))
+		}),
+		newDueRule(`(来|再来)(週|月|年)`, func(s *dueState, m []string) error {
+			unit := map[string]string{"週": "week", "月": "month", "年": "year"}[m[2]]
+			if m[1] == "来" {
+				return s.setNext(unit)
+			}
+			// the first day of the unit after the next.
+			if unit == "week" {
+				if err := s.setNext(unit); err != nil {
+					return err
+				}
+				s.date = s.date.AddDate(0, 0, 7)
+				return nil
+			}
+			today := s.today()
+			if unit == "month" {
+				return s.setDate(time.Date(today.Year(), today.Month()+2, 1, 0, 0, 0, 0, today.Location()))
+			}
+			return s.setDate(time.Date(today.Year()+2, time.January, 1, 0, 0, 0, 0, today.Location()))
+		}),
+		newDueRule(`(次の)?([日月火水木金土])曜日?`, func(s *dueState, m []string) error {
+			return s.setWeekday(japaneseWeekdays[m[2]], len(m[1]) != 0)
+		}),
+		newDueRule(`(?:(\d{4})年)?(\d{1,2})月(\d{1,2})日`, func(s *dueState, m []string) error {
+			return s.setDay(atoi(m[1]), time.Month(atoi(m[2])), atoi(m[3]))
+		}),
+		newDueRule(`(\d{1,2})日`, func(s *dueState, m []string) error {
+			// the day of this month, or of the next month if it has passed.
+			today := s.today()
+			day := atoi(m[1])
+			month := today.Month()
+			if day < today.Day() {
+				month++
+			}
+			date := time.Date(today.Year(), month, day, 0, 0, 0, 0, today.Location())
+			if date.Day() != day {
+				return fmt.Errorf("no such date %s", m[0])
+			}
+			return s.setDate(date)
+		}),
+		newDueRule(`(午前|午後)?(\d{1,2})時(?:(\d{1,2})分|(半))?`, func(s *dueState, m []string) error {
+			hour, minute := atoi(m[2]), atoi(m[3])
+			if len(m[4]) != 0 {
+				minute = 30
+			}
+			if len(m[1]) != 0 {
+				if 12 < hour {
+					return fmt.Errorf("no such time %s", m[0])
+				}
+				hour %= 12
+				if m[1] == "午後" {
+					hour += 12
+				}
+			}
+			return s.setTime(hour, minute)
+		}),
+		newDueRule(`正午`, func(s *dueState, m []string) error {
+			return s.setTime(12, 0)
+		}),
+		newDueRule(`(\d{1,2})/(\d{1,2})`, func(s *dueState, m []string) error {
+			return s.setDay(0, time.Month(atoi(m[1])), atoi(m[2]))
+		}),
+	}, dueCommonRules...),
+}
@@ -0,0 +1,99 @@
+package todoist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	loc := time.FixedZone("JST", 9*60*60)
+	// Wednesday
+	now := time.Date(2020, 5, 13, 10, 30, 0, 0, loc)
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2020, month, day, hour, minute, 0, 0, loc)
+	}
+	tests := []struct {
+		value     string
+		lang      string
+		expect    time.Time
+		fullDay   bool
+		recurring bool
+	}{
+		{"today", "", date(5, 13, 0, 0), true, false},
+		{"tomorrow 5pm", "en", date(5, 14, 17, 0), false, false},
+		{"Tomorrow at 17:30", "en", date(5, 14, 17, 30), false, false},
+		{"fri", "en", date(5, 15, 0, 0), true, false},
+		{"wed", "en", date(5, 13, 0, 0), true, false},
+		{"next wed", "en", date(5, 20, 0, 0), true, false},
+		{"next fri 9am", "en", date(5, 15, 9, 0), false, false},
+		{"next week", "en", date(5, 18, 0, 0), true, false},
+		{"next month", "en", date(6, 1, 0, 0), true, false},
+		{"in 3 days", "en", date(5, 16, 0, 0), true, false},
+		{"in 2 weeks", "en", date(5, 27, 0, 0), true, false},
+		{"in 2 hours", "en", date(5, 13, 12, 30), false, false},
+		{"in a month", "en", date(6, 13, 0, 0), true, false},
+		{"Jan 27", "en", time.Date(2021, 1, 27, 0, 0, 0, 0, loc), true, false},
+		{"June 1st", "en", date(6, 1, 0, 0), true, false},
+		{"27 dec noon", "en", date(12, 27, 12, 0), false, false},
+		{"jan 27, 2022", "en", time.Date(2022, 1, 27, 0, 0, 0, 0, loc), true, false},
+		{"2020-07-04 12:00", "en", date(7, 4, 12, 0), false, false},
+		{"5pm", "en", date(5, 13, 17, 0), false, false},
+		{"every day", "en", date(5, 13, 0, 0), true, true},
+		{"every mon", "en", date(5, 18, 0, 0), true, true},
+		{"今日", "ja", date(5, 13, 0, 0), true, false},
+		{"明日17時", "ja", date(5, 14, 17, 0), false, false},
+		{"明後日 午後3時半", "ja", date(5, 15, 15, 30), false, false},
+		{"金曜", "ja", date(5, 15, 0, 0), true, false},
+		{"来週の月曜日", "ja", date(5, 18, 0, 0), true, false},
+		{"来週金曜", "ja", date(5, 22, 0, 0), true, false},
+		{"次の水曜", "ja", date(5, 20, 0, 0), true, false},
+		{"3日後", "ja", date(5, 16, 0, 0), true, false},
+		{"１月２７日", "ja", time.Date(2021, 1, 27, 0, 0, 0, 0, loc), true, false},
+		{"2020年12月1日 9:00", "ja", date(12, 1, 9, 0), false, false},
+		{"来月", "ja", date(6, 1, 0, 0), true, false},
+		{"20日", "ja", date(5, 20, 0, 0), true, false},
+		{"毎日", "ja", date(5, 13, 0, 0), true, true},
+		{"毎週火曜", "ja", date(5, 19, 0, 0), true, true},
+	}
+	for _, test := range tests {
+		due, err := parseDue(test.value, test.lang, now)
+		if err != nil {
+			t.Errorf("%s: Unexpect error: %s", test.value, err)
+			continue
+		}
+		if !due.Date.Equal(Time{test.expect}) {
+			t.Errorf("%s: Expect %s, but got %s", test.value, test.expect, due.Date.Time.In(loc))
+		}
+		if due.Date.In(loc).IsFullDay() != test.fullDay {
+			t.Errorf("%s: Expect full day %v, but got %v", test.value, test.fullDay, !test.fullDay)
+		}
+		if due.IsRecurring != test.recurring {
+			t.Errorf("%s: Expect recurring %v, but got %v", test.value, test.recurring, due.IsRecurring)
+		}
+		if due.String != test.value {
+			t.Errorf("%s: Expect %s, but got %s", test.value, test.value, due.String)
+		}
+		if expect := map[bool]string{true: "", false: "JST"}[test.fullDay]; due.Timezone != expect {
+			t.Errorf("%s: Expect timezone %q, but got %q", test.value, expect, due.Timezone)
+		}
+	}
+
+	errors := []struct {
+		value string
+		lang  string
+	}{
+		{"someday", "en"},
+		{"feb 30", "en"},
+		{"13pm", "en"},
+		{"today tomorrow", "en"},
+		{"明日", "en"},
+		{"tomorrow", "ja"},
+		{"tomorrow", "xx"},
+		{"", "en"},
+	}
+	for _, test := range errors {
+		if due, err := parseDue(test.value, test.lang, now); err == nil {
+			t.Errorf("%s (%s): Expect error, but got %v", test.value, test.lang, due)
+		}
+	}
+}
@@ -76,13 +76,15 @@ type FilterClient struct {
 }
 
 func (c *FilterClient) Add(filter Filter) (*Filter, error) {
-	c.cache.store(filter)
 	command := Command{
 		Type:   "filter_add",
 		Args:   filter,
 		UUID:   GenerateUUID(),
 		TempID: filter.ID,
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.store(filter)
 	c.queue = append(c.queue, command)
 	return &filter, nil
 }
@@ -93,7 +95,7 @@ func (c *FilterClient) Update(filter Filter) (*Filter, error) {
 		Args: filter,
 		UUID: GenerateUUID(),
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return &filter, nil
 }
 
@@ -105,7 +107,7 @@ func (c *FilterClient) Delete(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
@@ -121,7 +123,7 @@ func (c *FilterClient) UpdateOrders(filters []Filter) error {
 			"id_order_mapping": args,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
@@ -135,7 +137,7 @@ func (c *FilterClient) Get(ctx context.Context, id ID) (*FilterGetResponse, erro
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -148,11 +150,15 @@ func (c *FilterClient) Get(ctx context.Context, id ID) (*FilterGetResponse, erro
 }
 
 func (c *FilterClient) GetAll() []Filter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.cache.getAll()
 }
 
 func (c *FilterClient) Resolve(id ID) *Filter {
-	return c.cache.resolve(id)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(c.tempIDs.Resolve(id))
 }
 
 func (c FilterClient) FindByName(substr string) []Filter {
@@ -166,47 +172,60 @@ func (c FilterClient) FindByName(substr string) []Filter {
 }
 
 type filterCache struct {
-	cache *[]Filter
+	filters map[ID]Filter
+	order   *orderedIDs
+}
+
+func newFilterCache() *filterCache {
+	c := &filterCache{}
+	c.reset()
+	return c
+}
+
+func (c *filterCache) reset() {
+	c.filters = map[ID]Filter{}
+	c.order = newOrderedIDs()
 }
 
 func (c *filterCache) getAll() []Filter {
-	return *c.cache
+	res := make([]Filter, 0, len(c.filters))
+	for _, id := range c.order.list() {
+		res = append(res, c.filters[id])
+	}
+	return res
 }
 
 func (c *filterCache) resolve(id ID) *Filter {
-	for _, filter := range *c.cache {
-		if filter.ID == id {
-			return &filter
-		}
+	if f, ok := c.filters[id]; ok {
+		return &f
 	}
 	return nil
 }
 
-func (c *filterCache) store(filter Filter) {
-	var res []Filter
-	isNew := true
-	for _, f := range *c.cache {
-		if f.Equal(filter) {
-			if !filter.IsDeleted {
-				res = append(res, filter)
-			}
-			isNew = false
-		} else {
-			res = append(res, f)
-		}
+func (c *filterCache) store(f Filter) {
+	if f.IsDeleted.Bool() {
+		c.remove(f)
+		return
 	}
-	if isNew && !filter.IsDeleted.Bool() {
-		res = append(res, filter)
+	c.filters[f.ID] = f
+	c.order.add(f.ID)
+}
+
+func (c *filterCache) remove(f Filter) {
+	_, ok := c.filters[f.ID]
+	if !ok {
+		return
 	}
-	c.cache = &res
+	delete(c.filters, f.ID)
+	c.order.remove(f.ID)
 }
 
-func (c *filterCache) remove(filter Filter) {
-	var res []Filter
-	for _, f := range *c.cache {
-		if !f.Equal(filter) {
-			res = append(res, f)
-		}
+func (c *filterCache) replaceTempIDs(mapping TempIDMapping) {
+	for _, id := range c.order.tempIDTargets(mapping) {
+		f := c.filters[id]
+		f.ID = mapping.Resolve(f.ID)
+		delete(c.filters, id)
+		c.order.rename(id, f.ID)
+		c.filters[f.ID] = f
 	}
-	c.cache = &res
 }
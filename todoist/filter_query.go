@@ -0,0 +1,346 @@
+package todoist
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterView is the items matching a view of a filter query.
+// Views are separated by commas, e.g. "today, overdue".
+type FilterView struct {
+	Query string
+	Items []Item
+}
+
+// Query evaluates the filter query against the cached unchecked items.
+//
+// Supported terms are "today", "tomorrow", "yesterday", "overdue" (or "od"), "no date",
+// "N days", "p1" to "p4", "#project", "##project" with its sub projects, "@label", "no labels",
+// "assigned", "assigned to: me|others|name", "recurring" and "search: text".
+// Terms are combined with "&", "|", "!" and parentheses, and special characters are escaped by "\".
+// Dates are compared in the timezone of the account.
+func (c FilterClient) Query(query string) ([]FilterView, error) {
+	return c.query(query, time.Now().In(c.Location()))
+}
+
+// query evaluates the filter query at now, in the timezone of now.
+func (c FilterClient) query(query string, now time.Time) ([]FilterView, error) {
+	parser := filterParser{client: c.Client, now: now}
+	views, err := splitFilterViews(query)
+	if err != nil {
+		return nil, err
+	}
+	var preds []itemPredicate
+	for _, view := range views {
+		pred, err := parser.parse(view)
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	items := c.Item.GetAll()
+	var res []FilterView
+	for i, pred := range preds {
+		view := FilterView{Query: views[i]}
+		for _, item := range items {
+			if !item.IsChecked() && pred(item) {
+				view.Items = append(view.Items, item)
+			}
+		}
+		res = append(res, view)
+	}
+	return res, nil
+}
+
+type itemPredicate func(item Item) bool
+
+// splitFilterViews splits the query by the commas which are not escaped nor in parentheses.
+func splitFilterViews(query string) ([]string, error) {
+	var views []string
+	depth, start := 0, 0
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				views = append(views, strings.TrimSpace(query[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	views = append(views, strings.TrimSpace(query[start:]))
+	for _, view := range views {
+		if len(view) == 0 {
+			return nil, fmt.Errorf("invalid filter query: %s", query)
+		}
+	}
+	return views, nil
+}
+
+type filterParser struct {
+	client *Client
+	now    time.Time
+	query  string
+	pos    int
+}
+
+// parse parses a view by the grammar below.
+//
+//	or   = and { "|" and }
+//	and  = not { "&" not }
+//	not  = "!" not | "(" or ")" | term
+func (p *filterParser) parse(query string) (itemPredicate, error) {
+	p.query = query
+	p.pos = 0
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos < len(p.query) {
+		return nil, fmt.Errorf("invalid filter query: unexpected %q in %s", p.query[p.pos], query)
+	}
+	return pred, nil
+}
+
+func (p *filterParser) skipSpaces() {
+	for p.pos < len(p.query) && p.query[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *filterParser) consume(op byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.query) && p.query[p.pos] == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (itemPredicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume('|') {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(item Item) bool { return l(item) || right(item) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (itemPredicate, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume('&') {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(item Item) bool { return l(item) && right(item) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (itemPredicate, error) {
+	if p.consume('!') {
+		pred, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(item Item) bool { return !pred(item) }, nil
+	}
+	if p.consume('(') {
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(')') {
+			return nil, fmt.Errorf("invalid filter query: missing \")\" in %s", p.query)
+		}
+		return pred, nil
+	}
+	term := p.readTerm()
+	if len(term) == 0 {
+		return nil, fmt.Errorf("invalid filter query: missing term in %s", p.query)
+	}
+	return p.compileTerm(term)
+}
+
+// readTerm reads the text until the next operator, unescaping escaped characters.
+func (p *filterParser) readTerm() string {
+	p.skipSpaces()
+	var b strings.Builder
+	for ; p.pos < len(p.query); p.pos++ {
+		ch := p.query[p.pos]
+		if ch == '\\' && p.pos+1 < len(p.query) {
+			p.pos++
+			b.WriteByte(p.query[p.pos])
+			continue
+		}
+		if strings.IndexByte("&|!()", ch) >= 0 {
+			break
+		}
+		b.WriteByte(ch)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+var daysTermPattern = regexp.MustCompile(`^(?:next )?(\d+) days?$`)
+
+func (p *filterParser) compileTerm(term string) (itemPredicate, error) {
+	lower := strings.ToLower(term)
+	switch lower {
+	case "today":
+		return p.dueInDays(0, 1), nil
+	case "tomorrow":
+		return p.dueInDays(1, 2), nil
+	case "yesterday":
+		return p.dueInDays(-1, 0), nil
+	case "overdue", "od":
+		return p.overdue, nil
+	case "no date", "no due date":
+		return func(item Item) bool { return item.Due.Date.IsZero() }, nil
+	case "p1", "p2", "p3", "p4":
+		// p1 is the highest priority, which is 4 in the api.
+		priority := 5 - int(lower[1]-'0')
+		return func(item Item) bool {
+			if item.Priority == 0 {
+				return priority == 1
+			}
+			return item.Priority == priority
+		}, nil
+	case "no labels":
+		return func(item Item) bool { return len(item.Labels) == 0 }, nil
+	case "recurring":
+		return func(item Item) bool { return item.Due.IsRecurring }, nil
+	case "assigned":
+		return func(item Item) bool { return !item.ResponsibleUID.IsZero() }, nil
+	}
+	if m := daysTermPattern.FindStringSubmatch(lower); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return p.dueInDays(0, n), nil
+	}
+	switch {
+	case strings.HasPrefix(term, "##"):
+		return p.project(term[2:], true)
+	case strings.HasPrefix(term, "#"):
+		return p.project(term[1:], false)
+	case strings.HasPrefix(term, "@"):
+		return p.label(term[1:])
+	case strings.HasPrefix(lower, "assigned to:"):
+		return p.assignedTo(strings.TrimSpace(term[len("assigned to:"):]))
+	case strings.HasPrefix(lower, "search:"):
+		text := strings.ToLower(strings.TrimSpace(term[len("search:"):]))
+		return func(item Item) bool { return strings.Contains(strings.ToLower(item.Content), text) }, nil
+	}
+	return nil, fmt.Errorf("unknown filter term: %s", term)
+}
+
+// dueInDays matches the items due from the from-th day to the day before the to-th day, counted from today.
+func (p *filterParser) dueInDays(from, to int) itemPredicate {
+	return func(item Item) bool {
+		if item.Due.Date.IsZero() {
+			return false
+		}
+		days := daysBetween(p.now, item.Due.Date.Time)
+		return from <= days && days < to
+	}
+}
+
+func (p *filterParser) overdue(item Item) bool {
+	if item.Due.Date.IsZero() {
+		return false
+	}
+	if item.Due.Date.In(p.now.Location()).IsFullDay() {
+		return daysBetween(p.now, item.Due.Date.Time) < 0
+	}
+	return item.Due.Date.Time.Before(p.now)
+}
+
+// daysBetween returns the number of calendar days from the day of now to the day of t in the timezone of now.
+func daysBetween(now, t time.Time) int {
+	loc := now.Location()
+	y1, m1, d1 := now.In(loc).Date()
+	y2, m2, d2 := t.In(loc).Date()
+	from := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func (p *filterParser) project(name string, withSubProjects bool) (itemPredicate, error) {
+	ids := map[ID]bool{}
+	for _, project := range p.client.Project.GetAll() {
+		if !strings.EqualFold(project.Name, name) {
+			continue
+		}
+		ids[project.ID] = true
+		if withSubProjects {
+			for _, sub := range p.client.Project.Descendants(project.ID) {
+				ids[sub.ID] = true
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no such project: %s", name)
+	}
+	return func(item Item) bool { return ids[item.ProjectID] }, nil
+}
+
+func (p *filterParser) label(name string) (itemPredicate, error) {
+	ids := map[ID]bool{}
+	for _, label := range p.client.Label.GetAll() {
+		if strings.EqualFold(label.Name, name) {
+			ids[label.ID] = true
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no such label: %s", name)
+	}
+	return func(item Item) bool {
+		for _, id := range item.Labels {
+			if ids[id] {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (p *filterParser) assignedTo(name string) (itemPredicate, error) {
+	var me ID
+	if user := p.client.User.Get(); user != nil {
+		me = user.ID
+	}
+	switch strings.ToLower(name) {
+	case "me":
+		return func(item Item) bool { return !me.IsZero() && item.ResponsibleUID == me }, nil
+	case "others":
+		return func(item Item) bool { return !item.ResponsibleUID.IsZero() && item.ResponsibleUID != me }, nil
+	}
+	ids := map[ID]bool{}
+	for _, collaborator := range p.client.Collaborator.GetAll() {
+		if strings.EqualFold(collaborator.FullName, name) || strings.EqualFold(collaborator.Email, name) {
+			ids[collaborator.ID] = true
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no such collaborator: %s", name)
+	}
+	return func(item Item) bool { return ids[item.ResponsibleUID] }, nil
+}
@@ -0,0 +1,135 @@
+package todoist
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilterClient_Query(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	project := func(id, parentID ID, name string) Project {
+		p := Project{Name: name, ParentID: parentID}
+		p.ID = id
+		return p
+	}
+	label := func(id ID, name string) Label {
+		l := Label{Name: name}
+		l.ID = id
+		return l
+	}
+	collaborator := func(id ID, name string) Collaborator {
+		c := Collaborator{FullName: name, Email: name + "@example.com"}
+		c.ID = id
+		return c
+	}
+	item := func(id, projectID ID, priority int, due string, labels ...ID) Item {
+		item := newTestItem(id, projectID, "", labels...)
+		item.Priority = priority
+		if len(due) != 0 {
+			d, err := ParseIn(due, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			item.Due = Due{Date: d}
+		}
+		return item
+	}
+	items := []Item{
+		item("1", "100", 4, "2020-05-10"),
+		item("2", "100", 1, "2020-05-09", "10"),
+		item("3", "101", 3, "2020-05-10T09:00:00", "10", "20"),
+		item("4", "102", 1, "2020-05-11"),
+		item("5", "200", 2, "", "20"),
+		item("6", "100", 1, "2020-05-16"),
+		item("7", "100", 4, ""),
+	}
+	items[0].Content = "write the report"
+	items[3].ResponsibleUID = "1000"
+	items[4].ResponsibleUID = "2000"
+	items[5].Due.IsRecurring = true
+	items[6].Checked = true
+	c.updateState(&SyncState{
+		FullSync: true,
+		User:     &User{ID: "1000"},
+		Items:    items,
+		Projects: []Project{
+			project("100", "", "Work"),
+			project("101", "100", "Backend"),
+			project("102", "101", "API"),
+			project("200", "", "Home & Garden"),
+		},
+		Labels:        []Label{label("10", "urgent"), label("20", "waiting")},
+		Collaborators: []Collaborator{collaborator("1000", "Alice"), collaborator("2000", "Bob")},
+	})
+	now := time.Date(2020, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		query  string
+		expect [][]ID
+	}{
+		{"today", [][]ID{{"1", "3"}}},
+		{"Tomorrow", [][]ID{{"4"}}},
+		{"overdue", [][]ID{{"2", "3"}}},
+		{"today | overdue", [][]ID{{"1", "2", "3"}}},
+		{"today, overdue", [][]ID{{"1", "3"}, {"2", "3"}}},
+		{"7 days", [][]ID{{"1", "3", "4", "6"}}},
+		{"no date", [][]ID{{"5"}}},
+		{"p1", [][]ID{{"1"}}},
+		{"p4 & !no date", [][]ID{{"2", "4", "6"}}},
+		{"#Work", [][]ID{{"1", "2", "6"}}},
+		{"##Work & !#Work", [][]ID{{"3", "4"}}},
+		{"#Home \\& Garden", [][]ID{{"5"}}},
+		{"@urgent", [][]ID{{"2", "3"}}},
+		{"@urgent & @waiting", [][]ID{{"3"}}},
+		{"no labels", [][]ID{{"1", "4", "6"}}},
+		{"!(@urgent | @waiting)", [][]ID{{"1", "4", "6"}}},
+		{"(today | overdue) & p2", [][]ID{{"3"}}},
+		{"assigned to: me", [][]ID{{"4"}}},
+		{"assigned to: others", [][]ID{{"5"}}},
+		{"assigned to: bob", [][]ID{{"5"}}},
+		{"assigned", [][]ID{{"4", "5"}}},
+		{"recurring", [][]ID{{"6"}}},
+		{"search: report", [][]ID{{"1"}}},
+		{"p1 & #Home \\& Garden", [][]ID{nil}},
+	}
+	for _, test := range tests {
+		views, err := c.Filter.query(test.query, now)
+		if err != nil {
+			t.Errorf("%s: Unexpect error: %s", test.query, err)
+			continue
+		}
+		var actual [][]ID
+		for _, view := range views {
+			var ids []ID
+			for _, item := range view.Items {
+				ids = append(ids, item.ID)
+			}
+			actual = append(actual, ids)
+		}
+		if !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("%s: Expect %v, but got %v", test.query, test.expect, actual)
+		}
+	}
+
+	errors := []struct {
+		query string
+		err   string
+	}{
+		{"today &", "invalid filter query: missing term in today &"},
+		{"(today | overdue", "invalid filter query: missing \")\" in (today | overdue"},
+		{"today)", "invalid filter query: unexpected ')' in today)"},
+		{"today,", "invalid filter query: today,"},
+		{"#Office", "no such project: Office"},
+		{"@later", "no such label: later"},
+		{"assigned to: carol", "no such collaborator: carol"},
+		{"someday", "unknown filter term: someday"},
+	}
+	for _, test := range errors {
+		if _, err := c.Filter.query(test.query, now); err == nil || err.Error() != test.err {
+			t.Errorf("%s: Expect %s, but got %v", test.query, test.err, err)
+		}
+	}
+}
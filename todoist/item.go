@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 )
@@ -13,6 +14,7 @@ type Item struct {
 	Entity
 	UserID         ID      `json:"user_id,omitempty"`
 	ProjectID      ID      `json:"project_id,omitempty"`
+	SectionID      ID      `json:"section_id,omitempty"`
 	Content        string  `json:"content"`
 	Due            Due     `json:"due,omitempty"`
 	Priority       int     `json:"priority,omitempty"`
@@ -32,6 +34,7 @@ type Item struct {
 
 type NewItemOpts struct {
 	ProjectID       ID
+	SectionID       ID
 	Due             Due
 	Priority        int
 	ParentID        ID
@@ -51,6 +54,7 @@ func NewItem(content string, opts *NewItemOpts) (*Item, error) {
 	}
 	item := Item{
 		ProjectID:      opts.ProjectID,
+		SectionID:      opts.SectionID,
 		Content:        content,
 		Due:            opts.Due,
 		ParentID:       opts.ParentID,
@@ -86,13 +90,15 @@ type ItemClient struct {
 func (c *ItemClient) Add(item Item) (*Item, error) {
 	// TODO: support auto_reminder and auto_parse_labels
 	// append item to sync state only `add` method?
-	c.cache.store(item)
 	command := Command{
 		Type:   "item_add",
 		Args:   item,
 		UUID:   GenerateUUID(),
 		TempID: item.ID,
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.store(item)
 	c.queue = append(c.queue, command)
 	return &item, nil
 }
@@ -103,7 +109,7 @@ func (c *ItemClient) Update(item Item) (*Item, error) {
 		Args: item,
 		UUID: GenerateUUID(),
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return &item, nil
 }
 
@@ -115,41 +121,49 @@ func (c *ItemClient) Delete(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
+// ItemMoveOpts specifies where to move the item. Exactly one of them is required.
 type ItemMoveOpts struct {
 	ParentID  ID
+	SectionID ID
 	ProjectID ID
 }
 
 func (c *ItemClient) Move(id ID, opts *ItemMoveOpts) error {
-	switch len(opts.ParentID) + len(opts.ProjectID) {
-	case 0:
-		return errors.New("require parent item id or project id")
-	case 2:
-		return errors.New("require either parent item id or project id")
-	}
 	args := map[string]interface{}{
 		"id": id,
 	}
-	if len(opts.ParentID) != 0 {
+	if !opts.ParentID.IsZero() {
 		args["parent_id"] = opts.ParentID
 	}
-	if len(opts.ProjectID) != 0 {
+	if !opts.SectionID.IsZero() {
+		args["section_id"] = opts.SectionID
+	}
+	if !opts.ProjectID.IsZero() {
 		args["project_id"] = opts.ProjectID
 	}
+	switch len(args) {
+	case 1:
+		return errors.New("require parent item id, section id or project id")
+	case 2:
+	default:
+		return errors.New("require either parent item id, section id or project id")
+	}
 
 	command := Command{
 		Type: "item_move",
 		UUID: GenerateUUID(),
 		Args: args,
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
+// Complete completes the item and its sub items, even if the item is recurring.
+// Use Close to move a recurring item to the next occurrence instead.
 func (c *ItemClient) Complete(id ID, dateCompleted Time, forceHistory bool) error {
 	var fh int
 	if forceHistory {
@@ -166,6 +180,9 @@ func (c *ItemClient) Complete(id ID, dateCompleted Time, forceHistory bool) erro
 			"force_history":  fh,
 		},
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.complete(c.tempIDs.Resolve(id), dateCompleted)
 	c.queue = append(c.queue, command)
 	return nil
 }
@@ -178,10 +195,12 @@ func (c *ItemClient) Uncomplete(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
+// Close completes the item and its sub items.
+// A recurring item is moved to the next occurrence and its sub items are uncompleted instead.
 func (c *ItemClient) Close(id ID) error {
 	command := Command{
 		Type: "item_close",
@@ -190,10 +209,67 @@ func (c *ItemClient) Close(id ID) error {
 			"id": id,
 		},
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.close(c.tempIDs.Resolve(id), Time{time.Now().UTC()}, c.location)
 	c.queue = append(c.queue, command)
 	return nil
 }
 
+// UpdateDayOrders sets the orders of the items in the Today view by their DayOrder.
+func (c *ItemClient) UpdateDayOrders(items []Item) error {
+	args := map[ID]int{}
+	for _, item := range items {
+		args[item.ID] = item.DayOrder
+	}
+	command := Command{
+		Type: "item_update_day_orders",
+		UUID: GenerateUUID(),
+		Args: map[string]map[ID]int{
+			"ids_to_orders": args,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+// DayOrders returns the orders of the items in the Today view set by the user.
+func (c *ItemClient) DayOrders() map[ID]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := map[ID]int{}
+	for id, order := range c.syncState.DayOrders {
+		res[id] = order
+	}
+	return res
+}
+
+// SortByDayOrder sorts the items by their day orders.
+// Items without day orders follow them in order of their due dates.
+func (c *ItemClient) SortByDayOrder(items []Item) {
+	orders := c.DayOrders()
+	order := func(item Item) (int, bool) {
+		if o, ok := orders[item.ID]; ok && o >= 0 {
+			return o, true
+		}
+		if item.DayOrder > 0 {
+			return item.DayOrder, true
+		}
+		return 0, false
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		oi, oki := order(items[i])
+		oj, okj := order(items[j])
+		switch {
+		case oki && okj:
+			return oi < oj
+		case oki != okj:
+			return oki
+		}
+		return items[i].Due.Date.Before(items[j].Due.Date)
+	})
+}
+
 type ItemGetResponse struct {
 	Item    Item
 	Project Project
@@ -206,7 +282,7 @@ func (c *ItemClient) Get(ctx context.Context, id ID) (*ItemGetResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +300,7 @@ func (c *ItemClient) GetCompleted(ctx context.Context, projectID ID) (*[]Item, e
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -237,24 +313,42 @@ func (c *ItemClient) GetCompleted(ctx context.Context, projectID ID) (*[]Item, e
 }
 
 func (c *ItemClient) GetAll() []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.cache.getAll()
 }
 
 func (c *ItemClient) Resolve(id ID) *Item {
-	return c.cache.resolve(id)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(c.tempIDs.Resolve(id))
 }
 
 func (c ItemClient) FindByProjectIDs(ids []ID) []Item {
-	var res []Item
-	for _, i := range c.GetAll() {
-		for _, pid := range ids {
-			if i.ProjectID == pid {
-				res = append(res, i)
-				break
-			}
-		}
-	}
-	return res
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.byProject, ids)
+}
+
+// FindBySectionID returns the items in the section.
+func (c ItemClient) FindBySectionID(id ID) []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.bySection, []ID{id})
+}
+
+// FindByParentID returns the direct children of the item.
+func (c ItemClient) FindByParentID(id ID) []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.byParent, []ID{id})
+}
+
+// FindByLabelIDs returns the items which have any of the labels.
+func (c ItemClient) FindByLabelIDs(ids []ID) []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.byLabel, ids)
 }
 
 func (c ItemClient) FindByContent(substr string) []Item {
@@ -277,50 +371,167 @@ func (c ItemClient) FindByDueDate(time Time) []Item {
 	return res
 }
 
+// complete checks the item and its sub items.
+func (c *itemCache) complete(id ID, dateCompleted Time) {
+	item, ok := c.items[id]
+	if !ok {
+		return
+	}
+	for _, i := range append([]Item{item}, c.subtree(id)...) {
+		if i.IsChecked() {
+			continue
+		}
+		i.Checked = true
+		i.CompletedDate = dateCompleted
+		c.store(i)
+	}
+}
+
+// close moves the recurring item to the next occurrence after the time in the timezone, and unchecks its sub items.
+// Other items are completed.
+func (c *itemCache) close(id ID, closed Time, loc *time.Location) {
+	item, ok := c.items[id]
+	if !ok {
+		return
+	}
+	if !item.Due.IsRecurring {
+		c.complete(id, closed)
+		return
+	}
+	// the server knows the next occurrence even if the recurrence cannot be parsed locally.
+	due, err := item.Due.Next(closed, loc)
+	if err != nil {
+		return
+	}
+	item.Due = *due
+	c.store(item)
+	for _, i := range c.subtree(id) {
+		if i.IsChecked() {
+			i.Checked = false
+			i.CompletedDate = Time{}
+			c.store(i)
+		}
+	}
+}
+
 type itemCache struct {
-	cache *[]Item
+	items     map[ID]Item
+	order     *orderedIDs
+	byProject idIndex
+	bySection idIndex
+	byParent  idIndex
+	byLabel   idIndex
+}
+
+func newItemCache() *itemCache {
+	c := &itemCache{}
+	c.reset()
+	return c
+}
+
+func (c *itemCache) reset() {
+	c.items = map[ID]Item{}
+	c.order = newOrderedIDs()
+	c.byProject = idIndex{}
+	c.bySection = idIndex{}
+	c.byParent = idIndex{}
+	c.byLabel = idIndex{}
 }
 
 func (c *itemCache) getAll() []Item {
-	return *c.cache
+	res := make([]Item, 0, len(c.items))
+	for _, id := range c.order.list() {
+		res = append(res, c.items[id])
+	}
+	return res
 }
 
-func (c *itemCache) resolve(id ID) *Item {
-	for _, item := range *c.cache {
-		if item.ID == id {
-			return &item
+// find returns the items indexed by any of the keys in insertion order.
+func (c *itemCache) find(index idIndex, keys []ID) []Item {
+	seen := map[ID]struct{}{}
+	var ids []ID
+	for _, key := range keys {
+		for _, id := range index.get(key) {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
 		}
 	}
+	c.order.sort(ids)
+	var res []Item
+	for _, id := range ids {
+		res = append(res, c.items[id])
+	}
+	return res
+}
+
+func (c *itemCache) resolve(id ID) *Item {
+	if item, ok := c.items[id]; ok {
+		return &item
+	}
 	return nil
 }
 
 func (c *itemCache) store(item Item) {
 	// sync api do not returns deleted items.
 	// so remove deleted items from cache too.
-	var res []Item
-	isNew := true
-	for _, i := range *c.cache {
-		if i.Equal(item) {
-			if !item.IsDeleted {
-				res = append(res, item)
-			}
-			isNew = false
-		} else {
-			res = append(res, i)
-		}
+	if item.IsDeleted.Bool() {
+		c.remove(item)
+		return
 	}
-	if isNew && !item.IsDeleted.Bool() {
-		res = append(res, item)
+	if old, ok := c.items[item.ID]; ok {
+		c.unindex(old)
 	}
-	c.cache = &res
+	c.items[item.ID] = item
+	c.order.add(item.ID)
+	c.index(item)
 }
 
 func (c *itemCache) remove(item Item) {
-	var res []Item
-	for _, i := range *c.cache {
-		if !i.Equal(item) {
-			res = append(res, i)
+	old, ok := c.items[item.ID]
+	if !ok {
+		return
+	}
+	c.unindex(old)
+	delete(c.items, item.ID)
+	c.order.remove(item.ID)
+}
+
+func (c *itemCache) index(item Item) {
+	c.byProject.add(item.ProjectID, item.ID)
+	c.bySection.add(item.SectionID, item.ID)
+	c.byParent.add(item.ParentID, item.ID)
+	for _, label := range item.Labels {
+		c.byLabel.add(label, item.ID)
+	}
+}
+
+func (c *itemCache) unindex(item Item) {
+	c.byProject.remove(item.ProjectID, item.ID)
+	c.bySection.remove(item.SectionID, item.ID)
+	c.byParent.remove(item.ParentID, item.ID)
+	for _, label := range item.Labels {
+		c.byLabel.remove(label, item.ID)
+	}
+}
+
+func (c *itemCache) replaceTempIDs(mapping TempIDMapping) {
+	for _, id := range c.order.tempIDTargets(mapping, c.byProject, c.bySection, c.byParent, c.byLabel) {
+		old := c.items[id]
+		i := old
+		i.ID = mapping.Resolve(i.ID)
+		i.ProjectID = mapping.Resolve(i.ProjectID)
+		i.SectionID = mapping.Resolve(i.SectionID)
+		i.ParentID = mapping.Resolve(i.ParentID)
+		i.Labels = mapping.resolveAll(i.Labels)
+		c.unindex(old)
+		delete(c.items, old.ID)
+		c.order.rename(old.ID, i.ID)
+		if existing, ok := c.items[i.ID]; ok {
+			c.unindex(existing)
 		}
+		c.items[i.ID] = i
+		c.index(i)
 	}
-	c.cache = &res
 }
@@ -0,0 +1,207 @@
+package todoist
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestItem(id, projectID, parentID ID, labels ...ID) Item {
+	item := Item{ProjectID: projectID, ParentID: parentID, Labels: labels, Content: "item " + id.String()}
+	item.ID = id
+	return item
+}
+
+func TestItemCache(t *testing.T) {
+	c := newItemCache()
+	c.store(newTestItem("1", "100", ""))
+	c.store(newTestItem("2", "200", "1", "10"))
+	c.store(newTestItem("3", "100", "1", "10", "20"))
+	c.store(newTestItem("2", "100", "", "20"))
+	deleted := newTestItem("1", "100", "")
+	deleted.IsDeleted = true
+	c.store(deleted)
+
+	ids := func(items []Item) []ID {
+		var res []ID
+		for _, i := range items {
+			res = append(res, i.ID)
+		}
+		return res
+	}
+	tests := []struct {
+		items  []Item
+		expect []ID
+	}{
+		{c.getAll(), []ID{"2", "3"}},
+		{c.find(c.byProject, []ID{"100", "200"}), []ID{"2", "3"}},
+		{c.find(c.byProject, []ID{"200"}), nil},
+		{c.find(c.byParent, []ID{"1"}), []ID{"3"}},
+		{c.find(c.byLabel, []ID{"10", "20"}), []ID{"2", "3"}},
+		{c.find(c.byLabel, []ID{"10"}), []ID{"3"}},
+	}
+	for i, test := range tests {
+		if got := ids(test.items); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("%d. Expect %v, but got %v", i, test.expect, got)
+		}
+	}
+	if c.resolve("1") != nil {
+		t.Errorf("Expect nil, but got %v", c.resolve("1"))
+	}
+}
+
+const benchmarkItems = 50000
+
+func newBenchmarkItems() []Item {
+	items := make([]Item, benchmarkItems)
+	for i := range items {
+		items[i] = newTestItem(ID(strconv.Itoa(i+1)), ID(strconv.Itoa(i%100+1)), "", ID(strconv.Itoa(i%10+1)))
+	}
+	return items
+}
+
+func BenchmarkClient_updateState(b *testing.B) {
+	c := newTestClient(b, "")
+	defer os.RemoveAll(c.CacheDir)
+	items := newBenchmarkItems()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		c.updateState(&SyncState{FullSync: true, Items: items})
+		c.updateState(&SyncState{Items: items})
+	}
+}
+
+func BenchmarkItemClient_Resolve(b *testing.B) {
+	c := newTestClient(b, "")
+	defer os.RemoveAll(c.CacheDir)
+	c.updateState(&SyncState{FullSync: true, Items: newBenchmarkItems()})
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		c.Item.Resolve(ID(strconv.Itoa(n%benchmarkItems + 1)))
+	}
+}
+
+func BenchmarkItemClient_FindByProjectIDs(b *testing.B) {
+	c := newTestClient(b, "")
+	defer os.RemoveAll(c.CacheDir)
+	c.updateState(&SyncState{FullSync: true, Items: newBenchmarkItems()})
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		c.Item.FindByProjectIDs([]ID{ID(fmt.Sprint(n%100 + 1))})
+	}
+}
+
+func TestItemClient_SortByDayOrder(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	c.updateState(&SyncState{
+		FullSync:           true,
+		DayOrders:          map[ID]int{"1": 2, "2": 1},
+		DayOrdersTimestamp: "1577836800.0",
+	})
+	// incremental syncs without day orders keep them.
+	c.updateState(&SyncState{})
+	if c.syncState.DayOrdersTimestamp != "1577836800.0" || len(c.Item.DayOrders()) != 2 {
+		t.Errorf("Unexpect day orders: %s, %v", c.syncState.DayOrdersTimestamp, c.Item.DayOrders())
+	}
+
+	due := func(item Item, day int) Item {
+		item.Due.Date = Time{time.Date(2020, 1, day, 0, 0, 0, 0, time.UTC)}
+		return item
+	}
+	items := []Item{
+		due(newTestItem("3", "", ""), 2),
+		due(newTestItem("1", "", ""), 3),
+		due(newTestItem("4", "", ""), 1),
+		due(newTestItem("2", "", ""), 4),
+	}
+	c.Item.SortByDayOrder(items)
+	var ids []ID
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	if expect := []ID{"2", "1", "4", "3"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+
+	items[0].DayOrder = 1
+	c.Item.UpdateDayOrders(items[:1])
+	args := c.Queue()[0].Args.(map[string]map[ID]int)
+	if args["ids_to_orders"]["2"] != 1 {
+		t.Errorf("Unexpect args: %v", args)
+	}
+}
+
+func TestItemClient_CompleteAndClose(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	today := time.Now().In(c.Location())
+	due := func(item Item, value string, days int) Item {
+		date := time.Date(today.Year(), today.Month(), today.Day()+days, 0, 0, 0, 0, c.Location())
+		item.Due = Due{Date: Time{date}, String: value, IsRecurring: strings.HasPrefix(value, "every")}
+		return item
+	}
+	checked := func(item Item) Item {
+		item.Checked = true
+		return item
+	}
+	c.updateState(&SyncState{FullSync: true, Items: []Item{
+		due(newTestItem("1", "100", ""), "every day", -2),
+		checked(newTestItem("2", "100", "1")),
+		newTestItem("3", "100", ""),
+		newTestItem("4", "100", "3"),
+		due(newTestItem("5", "100", ""), "every day", 0),
+		due(newTestItem("6", "100", ""), "every third workday", 0),
+	}})
+
+	c.Item.Close("1")
+	if item := c.Item.Resolve("1"); item.IsChecked() || !item.Due.Date.Equal(due(*item, "", 0).Due.Date) {
+		t.Errorf("Expect the recurring item due today, but got %v", item.Due.Date)
+	}
+	if item := c.Item.Resolve("2"); item.IsChecked() {
+		t.Error("Expect the sub item of the recurring item unchecked, but checked")
+	}
+	c.Item.Close("3")
+	if !c.Item.Resolve("3").IsChecked() || !c.Item.Resolve("4").IsChecked() {
+		t.Error("Expect the item and its sub item checked, but not checked")
+	}
+	c.Item.Complete("5", Time{time.Now().UTC()}, true)
+	if !c.Item.Resolve("5").IsChecked() {
+		t.Error("Expect the recurring item completed, but not checked")
+	}
+	// the recurrence which cannot be parsed is left to the server.
+	c.Item.Close("6")
+	if item := c.Item.Resolve("6"); item.IsChecked() || !item.Due.Date.Equal(due(*item, "", 0).Due.Date) {
+		t.Errorf("Expect the item unchanged, but got %v", item)
+	}
+	if len(c.Queue()) != 4 {
+		t.Errorf("Expect %d commands, but got %d", 4, len(c.Queue()))
+	}
+}
+
+func TestItemClient_Move(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	tests := []struct {
+		opts ItemMoveOpts
+		err  bool
+	}{
+		{ItemMoveOpts{}, true},
+		{ItemMoveOpts{SectionID: "10"}, false},
+		{ItemMoveOpts{ProjectID: "100"}, false},
+		{ItemMoveOpts{ParentID: "1", SectionID: "10"}, true},
+	}
+	for _, test := range tests {
+		err := c.Item.Move("1", &test.opts)
+		if test.err != (err != nil) {
+			t.Errorf("%v: Expect error %v, but got %v", test.opts, test.err, err)
+		}
+	}
+	if len(c.Queue()) != 2 {
+		t.Errorf("Expect %d, but got %d", 2, len(c.Queue()))
+	}
+}
@@ -0,0 +1,155 @@
+package todoist
+
+import (
+	"sort"
+)
+
+// ItemNode is an item with its sub items.
+type ItemNode struct {
+	Item     Item
+	Depth    int
+	Children []*ItemNode
+}
+
+// Walk calls the function for the node and its descendants in depth-first order.
+// The children of a node are skipped if the function returns false.
+func (n *ItemNode) Walk(f func(node *ItemNode) bool) {
+	if !f(n) {
+		return
+	}
+	for _, child := range n.Children {
+		child.Walk(f)
+	}
+}
+
+// Count returns the number of the descendants.
+func (n *ItemNode) Count() int {
+	count := 0
+	for _, child := range n.Children {
+		count += 1 + child.Count()
+	}
+	return count
+}
+
+// BuildItemForest builds trees of the items.
+// Items whose parents are not in the given items become roots.
+// Roots are grouped by the project in order of appearance, and siblings are ordered by ChildOrder.
+func BuildItemForest(items []Item) []*ItemNode {
+	nodes := map[ID]*ItemNode{}
+	for _, item := range items {
+		nodes[item.ID] = &ItemNode{Item: item}
+	}
+	projectOrder := map[ID]int{}
+	var roots []*ItemNode
+	for _, item := range items {
+		node := nodes[item.ID]
+		if parent, ok := nodes[item.ParentID]; ok && !item.ParentID.IsZero() && parent != node {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		if _, ok := projectOrder[item.ProjectID]; !ok {
+			projectOrder[item.ProjectID] = len(projectOrder)
+		}
+		roots = append(roots, node)
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		pi, pj := projectOrder[roots[i].Item.ProjectID], projectOrder[roots[j].Item.ProjectID]
+		if pi != pj {
+			return pi < pj
+		}
+		return roots[i].Item.ChildOrder < roots[j].Item.ChildOrder
+	})
+	// guard against cycles in broken data by visiting each node once.
+	visited := map[ID]bool{}
+	var res []*ItemNode
+	for _, root := range roots {
+		if setItemDepth(root, 0, visited) {
+			res = append(res, root)
+		}
+	}
+	return res
+}
+
+func setItemDepth(node *ItemNode, depth int, visited map[ID]bool) bool {
+	if visited[node.Item.ID] {
+		return false
+	}
+	visited[node.Item.ID] = true
+	node.Depth = depth
+	sortItemsByChildOrder(node.Children)
+	var children []*ItemNode
+	for _, child := range node.Children {
+		if setItemDepth(child, depth+1, visited) {
+			children = append(children, child)
+		}
+	}
+	node.Children = children
+	return true
+}
+
+func sortItemsByChildOrder(nodes []*ItemNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Item.ChildOrder < nodes[j].Item.ChildOrder
+	})
+}
+
+// Children returns the direct sub items of the item ordered by ChildOrder.
+func (c ItemClient) Children(id ID) []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.children(c.tempIDs.Resolve(id))
+}
+
+// Ancestors returns the parents of the item from the root to the direct parent.
+func (c ItemClient) Ancestors(id ID) []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []Item
+	seen := map[ID]bool{}
+	item, ok := c.cache.items[c.tempIDs.Resolve(id)]
+	for ok && !item.ParentID.IsZero() && !seen[item.ParentID] {
+		seen[item.ParentID] = true
+		if item, ok = c.cache.items[item.ParentID]; ok {
+			res = append([]Item{item}, res...)
+		}
+	}
+	return res
+}
+
+// Subtree returns all the descendants of the item in depth-first order.
+func (c ItemClient) Subtree(id ID) []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.subtree(c.tempIDs.Resolve(id))
+}
+
+// Forest returns the trees of the items in the project.
+func (c ItemClient) Forest(projectID ID) []*ItemNode {
+	return BuildItemForest(c.FindByProjectIDs([]ID{projectID}))
+}
+
+func (c *itemCache) subtree(id ID) []Item {
+	var res []Item
+	seen := map[ID]bool{}
+	var walk func(id ID)
+	walk = func(id ID) {
+		for _, child := range c.children(id) {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			res = append(res, child)
+			walk(child.ID)
+		}
+	}
+	walk(id)
+	return res
+}
+
+func (c *itemCache) children(id ID) []Item {
+	children := c.find(c.byParent, []ID{id})
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].ChildOrder < children[j].ChildOrder
+	})
+	return children
+}
@@ -0,0 +1,83 @@
+package todoist
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestItemTree(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	item := func(id, parentID ID, order int) Item {
+		i := newTestItem(id, "10", parentID)
+		i.ChildOrder = order
+		return i
+	}
+	// 1
+	//   3
+	//     5
+	//   2
+	// 4
+	items := []Item{item("1", "", 1), item("2", "1", 2), item("3", "1", 1), item("4", "", 2), item("5", "3", 1)}
+	c.updateState(&SyncState{FullSync: true, Items: items})
+
+	ids := func(items []Item) []ID {
+		var res []ID
+		for _, i := range items {
+			res = append(res, i.ID)
+		}
+		return res
+	}
+	tests := []struct {
+		name   string
+		actual []Item
+		expect []ID
+	}{
+		{"children", c.Item.Children("1"), []ID{"3", "2"}},
+		{"ancestors", c.Item.Ancestors("5"), []ID{"1", "3"}},
+		{"ancestors of root", c.Item.Ancestors("1"), nil},
+		{"subtree", c.Item.Subtree("1"), []ID{"3", "5", "2"}},
+	}
+	for _, test := range tests {
+		if actual := ids(test.actual); !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("%s: Expect %v, but got %v", test.name, test.expect, actual)
+		}
+	}
+
+	forest := c.Item.Forest("10")
+	var walked []ID
+	var depths []int
+	for _, root := range forest {
+		root.Walk(func(node *ItemNode) bool {
+			walked = append(walked, node.Item.ID)
+			depths = append(depths, node.Depth)
+			return node.Item.ID != "3"
+		})
+	}
+	if expect := []ID{"1", "3", "2", "4"}; !reflect.DeepEqual(walked, expect) {
+		t.Errorf("Expect %v, but got %v", expect, walked)
+	}
+	if expect := []int{0, 1, 1, 0}; !reflect.DeepEqual(depths, expect) {
+		t.Errorf("Expect %v, but got %v", expect, depths)
+	}
+	if forest[0].Count() != 3 {
+		t.Errorf("Expect %d, but got %d", 3, forest[0].Count())
+	}
+}
+
+func TestBuildItemForest(t *testing.T) {
+	// the parent of 2 is not given, and 3 and 4 are in a cycle.
+	a := newTestItem("1", "10", "")
+	b := newTestItem("2", "20", "9")
+	x := newTestItem("3", "10", "4")
+	y := newTestItem("4", "10", "3")
+	forest := BuildItemForest([]Item{a, b, x, y})
+	var roots []ID
+	for _, root := range forest {
+		roots = append(roots, root.Item.ID)
+	}
+	if expect := []ID{"1", "2"}; !reflect.DeepEqual(roots, expect) {
+		t.Errorf("Expect %v, but got %v", expect, roots)
+	}
+}
@@ -92,13 +92,15 @@ type LabelClient struct {
 }
 
 func (c *LabelClient) Add(label Label) (*Label, error) {
-	c.cache.store(label)
 	command := Command{
 		Type:   "label_add",
 		Args:   label,
 		UUID:   GenerateUUID(),
 		TempID: label.ID,
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.store(label)
 	c.queue = append(c.queue, command)
 	return &label, nil
 }
@@ -109,7 +111,7 @@ func (c *LabelClient) Update(label Label) (*Label, error) {
 		Args: label,
 		UUID: GenerateUUID(),
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return &label, nil
 }
 
@@ -121,7 +123,7 @@ func (c *LabelClient) Delete(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
@@ -137,7 +139,7 @@ func (c *LabelClient) UpdateOrders(labels []Label) error {
 			"id_order_mapping": args,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
@@ -151,7 +153,7 @@ func (c *LabelClient) Get(ctx context.Context, id ID) (*LabelGetResponse, error)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -164,11 +166,15 @@ func (c *LabelClient) Get(ctx context.Context, id ID) (*LabelGetResponse, error)
 }
 
 func (c *LabelClient) GetAll() []Label {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.cache.getAll()
 }
 
 func (c *LabelClient) Resolve(id ID) *Label {
-	return c.cache.resolve(id)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(c.tempIDs.Resolve(id))
 }
 
 func (c LabelClient) FindByName(substr string) []Label {
@@ -198,47 +204,60 @@ func (c LabelClient) FindOneByName(substr string) *Label {
 }
 
 type labelCache struct {
-	cache *[]Label
+	labels map[ID]Label
+	order  *orderedIDs
+}
+
+func newLabelCache() *labelCache {
+	c := &labelCache{}
+	c.reset()
+	return c
+}
+
+func (c *labelCache) reset() {
+	c.labels = map[ID]Label{}
+	c.order = newOrderedIDs()
 }
 
 func (c *labelCache) getAll() []Label {
-	return *c.cache
+	res := make([]Label, 0, len(c.labels))
+	for _, id := range c.order.list() {
+		res = append(res, c.labels[id])
+	}
+	return res
 }
 
 func (c *labelCache) resolve(id ID) *Label {
-	for _, label := range *c.cache {
-		if label.ID == id {
-			return &label
-		}
+	if l, ok := c.labels[id]; ok {
+		return &l
 	}
 	return nil
 }
 
-func (c *labelCache) store(label Label) {
-	var res []Label
-	isNew := true
-	for _, l := range *c.cache {
-		if l.Equal(label) {
-			if !label.IsDeleted {
-				res = append(res, label)
-			}
-			isNew = false
-		} else {
-			res = append(res, l)
-		}
+func (c *labelCache) store(l Label) {
+	if l.IsDeleted.Bool() {
+		c.remove(l)
+		return
 	}
-	if isNew && !label.IsDeleted.Bool() {
-		res = append(res, label)
+	c.labels[l.ID] = l
+	c.order.add(l.ID)
+}
+
+func (c *labelCache) remove(l Label) {
+	_, ok := c.labels[l.ID]
+	if !ok {
+		return
 	}
-	c.cache = &res
+	delete(c.labels, l.ID)
+	c.order.remove(l.ID)
 }
 
-func (c *labelCache) remove(label Label) {
-	var res []Label
-	for _, l := range *c.cache {
-		if !l.Equal(label) {
-			res = append(res, l)
-		}
+func (c *labelCache) replaceTempIDs(mapping TempIDMapping) {
+	for _, id := range c.order.tempIDTargets(mapping) {
+		l := c.labels[id]
+		l.ID = mapping.Resolve(l.ID)
+		delete(c.labels, id)
+		c.order.rename(id, l.ID)
+		c.labels[l.ID] = l
 	}
-	c.cache = &res
 }
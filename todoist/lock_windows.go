@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package todoist
+
+import (
+	"golang.org/x/sys/windows"
+	"os"
+)
+
+// lockFile acquires an exclusive lock of the file shared between processes with LockFileEx.
+// The lock is released by the system when the process exits, so it never becomes stale.
+func lockFile(file string) (func() error, error) {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	// lock the first byte, which is enough as all processes lock the same range.
+	ol := new(windows.Overlapped)
+	if err = windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, ol); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() error {
+		defer f.Close()
+		return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+	}, nil
+}
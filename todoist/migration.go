@@ -0,0 +1,62 @@
+package todoist
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CacheVersion is the schema version of the cached state.
+// Increment it and register a migration in cacheMigrations when SyncState changes incompatibly.
+const CacheVersion = 1
+
+// cacheMigrations converts the cached state of the version to the next version.
+var cacheMigrations = map[int]func(json.RawMessage) (json.RawMessage, error){
+	// version 0 is the bare SyncState written before the cache was versioned.
+	0: func(state json.RawMessage) (json.RawMessage, error) {
+		return state, nil
+	},
+}
+
+// cacheEnvelope wraps the cached state with its schema version.
+type cacheEnvelope struct {
+	Version int             `json:"version"`
+	State   json.RawMessage `json:"state"`
+}
+
+func encodeCache(state SyncState) ([]byte, error) {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(cacheEnvelope{Version: CacheVersion, State: b}, "", "  ")
+}
+
+func decodeCache(b []byte) (*SyncState, error) {
+	var envelope cacheEnvelope
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.State == nil {
+		// unversioned cache
+		envelope = cacheEnvelope{Version: 0, State: b}
+	}
+	if envelope.Version > CacheVersion {
+		return nil, fmt.Errorf("cache version %d is newer than supported version %d", envelope.Version, CacheVersion)
+	}
+	state := envelope.State
+	for v := envelope.Version; v < CacheVersion; v++ {
+		migrate, ok := cacheMigrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration of cache version %d", v)
+		}
+		var err error
+		if state, err = migrate(state); err != nil {
+			return nil, fmt.Errorf("failed to migrate cache version %d: %s", v, err)
+		}
+	}
+	var s SyncState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,49 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeCache(t *testing.T) {
+	state := SyncState{Items: []Item{{Content: "foo"}}}
+	encoded, err := encodeCache(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope cacheEnvelope
+	if err = json.Unmarshal(encoded, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Version != CacheVersion {
+		t.Errorf("Expect %d, but got %d", CacheVersion, envelope.Version)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+		err  bool
+	}{
+		{"current", string(encoded), "foo", false},
+		{"unversioned", `{"items": [{"id": 1, "content": "bar"}]}`, "bar", false},
+		{"newer", `{"version": 999, "state": {"items": []}}`, "", true},
+		{"broken", `{"items": [`, "", true},
+	}
+	for _, test := range tests {
+		actual, err := decodeCache([]byte(test.in))
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: Expect error, but got nil", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Unexpect error: %s", test.name, err)
+			continue
+		}
+		if len(actual.Items) != 1 || actual.Items[0].Content != test.want {
+			t.Errorf("%s: Expect %s, but got %v", test.name, test.want, actual.Items)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package todoist
 
-import "errors"
+import (
+	"errors"
+)
 
 type Note struct {
 	Entity
@@ -48,13 +50,15 @@ type NoteClient struct {
 }
 
 func (c NoteClient) Add(note Note) (*Note, error) {
-	c.cache.store(note)
 	command := Command{
 		Type:   "note_add",
 		Args:   note,
 		UUID:   GenerateUUID(),
 		TempID: note.ID,
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.store(note)
 	c.queue = append(c.queue, command)
 	return &note, nil
 }
@@ -65,7 +69,7 @@ func (c NoteClient) Update(note Note) (*Note, error) {
 		Args: note,
 		UUID: GenerateUUID(),
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return &note, nil
 }
 
@@ -77,26 +81,24 @@ func (c NoteClient) Delete(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
 // GetAllForItem returns all the cached notes that belong to the given item.
 func (c NoteClient) GetAllForItem(itemID ID) []Note {
-	var res []Note
-	for _, n := range c.cache.getAll() {
-		if n.ItemID == itemID {
-			res = append(res, n)
-		}
-	}
-	return res
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.byItem, []ID{itemID})
 }
 
 // GetAllForProject returns all the cached notes that belong to the given project.
 func (c NoteClient) GetAllForProject(projectID ID) []Note {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	var res []Note
-	for _, n := range c.cache.getAll() {
-		if n.ProjectID == projectID && n.ItemID == "" {
+	for _, n := range c.cache.find(c.cache.byProject, []ID{projectID}) {
+		if n.ItemID.IsZero() {
 			res = append(res, n)
 		}
 	}
@@ -104,28 +106,100 @@ func (c NoteClient) GetAllForProject(projectID ID) []Note {
 }
 
 type noteCache struct {
-	cache *[]Note
+	notes     map[ID]Note
+	order     *orderedIDs
+	byItem    idIndex
+	byProject idIndex
+}
+
+func newNoteCache() *noteCache {
+	c := &noteCache{}
+	c.reset()
+	return c
+}
+
+func (c *noteCache) reset() {
+	c.notes = map[ID]Note{}
+	c.order = newOrderedIDs()
+	c.byItem = idIndex{}
+	c.byProject = idIndex{}
 }
 
 func (c *noteCache) getAll() []Note {
-	return *c.cache
+	res := make([]Note, 0, len(c.notes))
+	for _, id := range c.order.list() {
+		res = append(res, c.notes[id])
+	}
+	return res
 }
 
-func (c *noteCache) store(note Note) {
-	var res []Note
-	isNew := true
-	for _, n := range *c.cache {
-		if n.Equal(note) {
-			if !note.IsDeleted {
-				res = append(res, note)
+// find returns the notes indexed by any of the keys in insertion order.
+func (c *noteCache) find(index idIndex, keys []ID) []Note {
+	seen := map[ID]struct{}{}
+	var ids []ID
+	for _, key := range keys {
+		for _, id := range index.get(key) {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
 			}
-			isNew = false
-		} else {
-			res = append(res, n)
 		}
 	}
-	if isNew && !note.IsDeleted.Bool() {
-		res = append(res, note)
+	c.order.sort(ids)
+	var res []Note
+	for _, id := range ids {
+		res = append(res, c.notes[id])
+	}
+	return res
+}
+
+func (c *noteCache) store(n Note) {
+	if n.IsDeleted.Bool() {
+		c.remove(n)
+		return
+	}
+	if old, ok := c.notes[n.ID]; ok {
+		c.unindex(old)
+	}
+	c.notes[n.ID] = n
+	c.order.add(n.ID)
+	c.index(n)
+}
+
+func (c *noteCache) remove(n Note) {
+	old, ok := c.notes[n.ID]
+	if !ok {
+		return
+	}
+	c.unindex(old)
+	delete(c.notes, n.ID)
+	c.order.remove(n.ID)
+}
+
+func (c *noteCache) index(n Note) {
+	c.byItem.add(n.ItemID, n.ID)
+	c.byProject.add(n.ProjectID, n.ID)
+}
+
+func (c *noteCache) unindex(n Note) {
+	c.byItem.remove(n.ItemID, n.ID)
+	c.byProject.remove(n.ProjectID, n.ID)
+}
+
+func (c *noteCache) replaceTempIDs(mapping TempIDMapping) {
+	for _, id := range c.order.tempIDTargets(mapping, c.byItem, c.byProject) {
+		old := c.notes[id]
+		n := old
+		n.ID = mapping.Resolve(n.ID)
+		n.ItemID = mapping.Resolve(n.ItemID)
+		n.ProjectID = mapping.Resolve(n.ProjectID)
+		c.unindex(old)
+		delete(c.notes, old.ID)
+		c.order.rename(old.ID, n.ID)
+		if existing, ok := c.notes[n.ID]; ok {
+			c.unindex(existing)
+		}
+		c.notes[n.ID] = n
+		c.index(n)
 	}
-	c.cache = &res
 }
@@ -0,0 +1,211 @@
+package todoist
+
+import (
+	"fmt"
+)
+
+type LiveNotification struct {
+	Entity
+	CreatedDate      Time    `json:"created_date"`
+	FromUID          ID      `json:"from_uid"`
+	NotificationKey  string  `json:"notification_key"`
+	NotificationType string  `json:"notification_type"`
+	SeqNo            int64   `json:"seq_no"`
+	IsUnread         IntBool `json:"is_unread"`
+	FromUser         struct {
+		Email    string `json:"email"`
+		FullName string `json:"full_name"`
+		ID       ID     `json:"id"`
+		ImageID  string `json:"image_id"`
+	} `json:"from_user"`
+	ProjectID        ID     `json:"project_id"`
+	ProjectName      string `json:"project_name"`
+	ItemID           ID     `json:"item_id"`
+	ItemContent      string `json:"item_content"`
+	NoteID           ID     `json:"note_id"`
+	NoteContent      string `json:"note_content"`
+	ResponsibleUID   ID     `json:"responsible_uid"`
+	InvitationID     ID     `json:"invitation_id"`
+	InvitationSecret string `json:"invitation_secret"`
+	State            string `json:"state"`
+	AccountName      string `json:"account_name"`
+	KarmaLevel       int    `json:"karma_level"`
+}
+
+// Message describes the notification with the name of the project or the item.
+// The project and the item are resolved by the relations if they are cached.
+func (n LiveNotification) Message(relations NotificationRelations) string {
+	from := n.FromUser.FullName
+	if len(from) == 0 {
+		from = n.FromUser.Email
+	}
+	if u, ok := relations.Users[n.FromUID]; ok {
+		from = u.String()
+	}
+	if len(from) == 0 {
+		from = "someone"
+	}
+	project := "#" + n.ProjectName
+	if p, ok := relations.Projects[n.ProjectID]; ok {
+		project = p.String()
+	}
+	item := n.ItemContent
+	if i, ok := relations.Items[n.ItemID]; ok {
+		item = i.Content
+	}
+	switch n.NotificationType {
+	case "share_invitation_sent":
+		return fmt.Sprintf("%s invited you to %s", from, project)
+	case "share_invitation_accepted":
+		return fmt.Sprintf("%s accepted your invitation to %s", from, project)
+	case "share_invitation_rejected":
+		return fmt.Sprintf("%s rejected your invitation to %s", from, project)
+	case "user_left_project":
+		return fmt.Sprintf("%s left %s", from, project)
+	case "user_removed_from_project":
+		return fmt.Sprintf("%s removed you from %s", from, project)
+	case "item_assigned":
+		return fmt.Sprintf("%s assigned %s to you", from, item)
+	case "item_completed":
+		return fmt.Sprintf("%s completed %s", from, item)
+	case "item_uncompleted":
+		return fmt.Sprintf("%s uncompleted %s", from, item)
+	case "note_added":
+		return fmt.Sprintf("%s commented on %s: %s", from, item, n.NoteContent)
+	case "karma_level":
+		return fmt.Sprintf("you reached karma level %d", n.KarmaLevel)
+	case "biz_invitation_created":
+		return fmt.Sprintf("%s invited you to %s", from, n.AccountName)
+	}
+	return n.NotificationType
+}
+
+type NotificationClient struct {
+	*Client
+	cache *notificationCache
+}
+
+// MarkRead marks the cached notification as read.
+func (c NotificationClient) MarkRead(id ID) error {
+	if c.Resolve(id) == nil {
+		return fmt.Errorf("invalid notification id: %s", id)
+	}
+	command := Command{
+		Type: "live_notifications_mark_read",
+		UUID: GenerateUUID(),
+		Args: map[string]ID{
+			"id": id,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+// MarkUnread marks the cached notification as unread.
+func (c NotificationClient) MarkUnread(id ID) error {
+	if c.Resolve(id) == nil {
+		return fmt.Errorf("invalid notification id: %s", id)
+	}
+	command := Command{
+		Type: "live_notifications_mark_unread",
+		UUID: GenerateUUID(),
+		Args: map[string]ID{
+			"id": id,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+// MarkAllRead marks all the notifications as read.
+// It does nothing if no cached notification is unread.
+func (c NotificationClient) MarkAllRead() error {
+	if len(c.GetUnread()) == 0 {
+		return nil
+	}
+	command := Command{
+		Type: "live_notifications_mark_read_all",
+		UUID: GenerateUUID(),
+		Args: map[string]interface{}{},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c NotificationClient) GetAll() []LiveNotification {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.getAll()
+}
+
+func (c NotificationClient) Resolve(id ID) *LiveNotification {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(id)
+}
+
+// GetUnread returns the unread notifications.
+func (c NotificationClient) GetUnread() []LiveNotification {
+	var res []LiveNotification
+	for _, n := range c.GetAll() {
+		if n.IsUnread.Bool() {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// LastReadID returns the id of the last read notification.
+func (c NotificationClient) LastReadID() ID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.syncState.LiveNotificationsLastReadID
+}
+
+type notificationCache struct {
+	notifications map[ID]LiveNotification
+	order         *orderedIDs
+}
+
+func newNotificationCache() *notificationCache {
+	c := &notificationCache{}
+	c.reset()
+	return c
+}
+
+func (c *notificationCache) reset() {
+	c.notifications = map[ID]LiveNotification{}
+	c.order = newOrderedIDs()
+}
+
+func (c *notificationCache) getAll() []LiveNotification {
+	res := make([]LiveNotification, 0, len(c.notifications))
+	for _, id := range c.order.list() {
+		res = append(res, c.notifications[id])
+	}
+	return res
+}
+
+func (c *notificationCache) resolve(id ID) *LiveNotification {
+	if n, ok := c.notifications[id]; ok {
+		return &n
+	}
+	return nil
+}
+
+func (c *notificationCache) store(n LiveNotification) {
+	if n.IsDeleted.Bool() {
+		c.remove(n)
+		return
+	}
+	c.notifications[n.ID] = n
+	c.order.add(n.ID)
+}
+
+func (c *notificationCache) remove(n LiveNotification) {
+	if _, ok := c.notifications[n.ID]; !ok {
+		return
+	}
+	delete(c.notifications, n.ID)
+	c.order.remove(n.ID)
+}
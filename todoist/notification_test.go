@@ -0,0 +1,56 @@
+package todoist
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNotificationClient(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	var state SyncState
+	if err := json.Unmarshal([]byte(`{
+		"live_notifications": [
+			{"id": 1, "created_date": 1577836800, "from_uid": 2, "notification_type": "share_invitation_sent", "is_unread": 1, "project_id": 10, "project_name": "Work", "from_user": {"id": 2, "email": "foo@example.com", "full_name": "Foo"}},
+			{"id": 2, "created_date": 1577836900, "from_uid": 2, "notification_type": "item_assigned", "is_unread": 1, "item_id": 100, "item_content": "old content"},
+			{"id": 3, "created_date": 1577837000, "from_uid": 2, "notification_type": "note_added", "is_unread": 0, "item_id": 100, "note_content": "hi"}
+		],
+		"live_notifications_last_read_id": 3,
+		"items": [{"id": 100, "content": "write tests"}]
+	}`), &state); err != nil {
+		t.Fatal(err)
+	}
+	state.FullSync = true
+	c.updateState(&state)
+
+	unread := c.Notification.GetUnread()
+	if len(unread) != 2 {
+		t.Fatalf("Expect %d, but got %d", 2, len(unread))
+	}
+	if !unread[0].CreatedDate.Time.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpect created date: %s", unread[0].CreatedDate.Time)
+	}
+	if c.Notification.LastReadID() != "3" {
+		t.Errorf("Expect %s, but got %s", "3", c.Notification.LastReadID())
+	}
+	relations := c.Relation.LiveNotifications(unread)
+	expects := []string{"Foo invited you to #Work", "someone assigned write tests to you"}
+	for i, n := range unread {
+		if msg := n.Message(relations); msg != expects[i] {
+			t.Errorf("Expect %s, but got %s", expects[i], msg)
+		}
+	}
+
+	if err := c.Notification.MarkRead("1"); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if err := c.Notification.MarkUnread("999"); err == nil {
+		t.Error("Expect error, but got nil")
+	}
+	c.Notification.MarkAllRead()
+	if len(c.Queue()) != 2 {
+		t.Errorf("Expect %d, but got %d", 2, len(c.Queue()))
+	}
+}
@@ -82,13 +82,15 @@ type ProjectClient struct {
 }
 
 func (c *ProjectClient) Add(project Project) (*Project, error) {
-	c.cache.store(project)
 	command := Command{
 		Type:   "project_add",
 		Args:   project,
 		UUID:   GenerateUUID(),
 		TempID: project.ID,
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.store(project)
 	c.queue = append(c.queue, command)
 	return &project, nil
 }
@@ -99,7 +101,7 @@ func (c *ProjectClient) Update(project Project) (*Project, error) {
 		Args: project,
 		UUID: GenerateUUID(),
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return &project, nil
 }
 
@@ -112,7 +114,7 @@ func (c *ProjectClient) Move(id, parentID ID) error {
 			"parent_id": parentID,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 
 }
@@ -125,7 +127,21 @@ func (c *ProjectClient) Delete(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
+	return nil
+}
+
+// Share invites the user of the email to the project.
+func (c *ProjectClient) Share(id ID, email string) error {
+	command := Command{
+		Type: "share_project",
+		UUID: GenerateUUID(),
+		Args: map[string]interface{}{
+			"project_id": id,
+			"email":      email,
+		},
+	}
+	c.enqueue(command)
 	return nil
 }
 
@@ -137,7 +153,7 @@ func (c *ProjectClient) Archive(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
@@ -149,7 +165,7 @@ func (c *ProjectClient) Unarchive(id ID) error {
 			"id": id,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
@@ -161,7 +177,7 @@ func (c *ProjectClient) Reorder(projects []Project) error {
 			"projects": projects,
 		},
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue(command)
 	return nil
 }
 
@@ -176,7 +192,7 @@ func (c *ProjectClient) Get(ctx context.Context, id ID) (*ProjectGetResponse, er
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -199,7 +215,7 @@ func (c *ProjectClient) GetData(ctx context.Context, id ID) (*ProjectGetDataResp
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -217,7 +233,7 @@ func (c *ProjectClient) GetArchived(ctx context.Context) (*[]Project, error) {
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -230,11 +246,22 @@ func (c *ProjectClient) GetArchived(ctx context.Context) (*[]Project, error) {
 }
 
 func (c *ProjectClient) GetAll() []Project {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.cache.getAll()
 }
 
 func (c *ProjectClient) Resolve(id ID) *Project {
-	return c.cache.resolve(id)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(c.tempIDs.Resolve(id))
+}
+
+// FindByParentID returns the direct children of the project.
+func (c ProjectClient) FindByParentID(id ID) []Project {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.byParent, []ID{id})
 }
 
 func (c ProjectClient) FindByName(substr string) []Project {
@@ -264,47 +291,102 @@ func (c ProjectClient) FindOneByName(substr string) *Project {
 }
 
 type projectCache struct {
-	cache *[]Project
+	projects map[ID]Project
+	order    *orderedIDs
+	byParent idIndex
+}
+
+func newProjectCache() *projectCache {
+	c := &projectCache{}
+	c.reset()
+	return c
+}
+
+func (c *projectCache) reset() {
+	c.projects = map[ID]Project{}
+	c.order = newOrderedIDs()
+	c.byParent = idIndex{}
 }
 
 func (c *projectCache) getAll() []Project {
-	return *c.cache
+	res := make([]Project, 0, len(c.projects))
+	for _, id := range c.order.list() {
+		res = append(res, c.projects[id])
+	}
+	return res
 }
 
-func (c *projectCache) resolve(id ID) *Project {
-	for _, project := range *c.cache {
-		if project.ID == id {
-			return &project
+// find returns the projects indexed by any of the keys in insertion order.
+func (c *projectCache) find(index idIndex, keys []ID) []Project {
+	seen := map[ID]struct{}{}
+	var ids []ID
+	for _, key := range keys {
+		for _, id := range index.get(key) {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
 		}
 	}
+	c.order.sort(ids)
+	var res []Project
+	for _, id := range ids {
+		res = append(res, c.projects[id])
+	}
+	return res
+}
+
+func (c *projectCache) resolve(id ID) *Project {
+	if p, ok := c.projects[id]; ok {
+		return &p
+	}
 	return nil
 }
 
-func (c *projectCache) store(project Project) {
-	var res []Project
-	isNew := true
-	for _, p := range *c.cache {
-		if p.Equal(project) {
-			if !project.IsDeleted {
-				res = append(res, project)
-			}
-			isNew = false
-		} else {
-			res = append(res, p)
-		}
+func (c *projectCache) store(p Project) {
+	if p.IsDeleted.Bool() {
+		c.remove(p)
+		return
 	}
-	if isNew && !project.IsDeleted.Bool() {
-		res = append(res, project)
+	if old, ok := c.projects[p.ID]; ok {
+		c.unindex(old)
 	}
-	c.cache = &res
+	c.projects[p.ID] = p
+	c.order.add(p.ID)
+	c.index(p)
 }
 
-func (c *projectCache) remove(project Project) {
-	var res []Project
-	for _, p := range *c.cache {
-		if !p.Equal(project) {
-			res = append(res, p)
+func (c *projectCache) remove(p Project) {
+	old, ok := c.projects[p.ID]
+	if !ok {
+		return
+	}
+	c.unindex(old)
+	delete(c.projects, p.ID)
+	c.order.remove(p.ID)
+}
+
+func (c *projectCache) index(p Project) {
+	c.byParent.add(p.ParentID, p.ID)
+}
+
+func (c *projectCache) unindex(p Project) {
+	c.byParent.remove(p.ParentID, p.ID)
+}
+
+func (c *projectCache) replaceTempIDs(mapping TempIDMapping) {
+	for _, id := range c.order.tempIDTargets(mapping, c.byParent) {
+		old := c.projects[id]
+		p := old
+		p.ID = mapping.Resolve(p.ID)
+		p.ParentID = mapping.Resolve(p.ParentID)
+		c.unindex(old)
+		delete(c.projects, old.ID)
+		c.order.rename(old.ID, p.ID)
+		if existing, ok := c.projects[p.ID]; ok {
+			c.unindex(existing)
 		}
+		c.projects[p.ID] = p
+		c.index(p)
 	}
-	c.cache = &res
 }
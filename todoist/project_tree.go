@@ -0,0 +1,239 @@
+package todoist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProjectNode is a project with its sub projects.
+type ProjectNode struct {
+	Project  Project
+	Depth    int
+	Children []*ProjectNode
+}
+
+// Walk calls the function for the node and its descendants in depth-first order.
+// The children of a node are skipped if the function returns false.
+func (n *ProjectNode) Walk(f func(node *ProjectNode) bool) {
+	if !f(n) {
+		return
+	}
+	for _, child := range n.Children {
+		child.Walk(f)
+	}
+}
+
+// Count returns the number of the descendants.
+func (n *ProjectNode) Count() int {
+	count := 0
+	for _, child := range n.Children {
+		count += 1 + child.Count()
+	}
+	return count
+}
+
+// BuildProjectForest builds trees of the projects regardless of their order.
+// Projects whose parents are not in the given projects become roots, and siblings are ordered by ChildOrder.
+func BuildProjectForest(projects []Project) []*ProjectNode {
+	nodes := map[ID]*ProjectNode{}
+	for _, project := range projects {
+		nodes[project.ID] = &ProjectNode{Project: project}
+	}
+	var roots []*ProjectNode
+	for _, project := range projects {
+		node := nodes[project.ID]
+		if parent, ok := nodes[project.ParentID]; ok && !project.ParentID.IsZero() && parent != node {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	sortProjectsByChildOrder(roots)
+	// guard against cycles in broken data by visiting each node once.
+	visited := map[ID]bool{}
+	var res []*ProjectNode
+	for _, root := range roots {
+		if setProjectDepth(root, 0, visited) {
+			res = append(res, root)
+		}
+	}
+	return res
+}
+
+func setProjectDepth(node *ProjectNode, depth int, visited map[ID]bool) bool {
+	if visited[node.Project.ID] {
+		return false
+	}
+	visited[node.Project.ID] = true
+	node.Depth = depth
+	sortProjectsByChildOrder(node.Children)
+	var children []*ProjectNode
+	for _, child := range node.Children {
+		if setProjectDepth(child, depth+1, visited) {
+			children = append(children, child)
+		}
+	}
+	node.Children = children
+	return true
+}
+
+func sortProjectsByChildOrder(nodes []*ProjectNode) {
+	// the inbox always comes first.
+	sort.SliceStable(nodes, func(i, j int) bool {
+		pi, pj := nodes[i].Project, nodes[j].Project
+		if pi.InboxProject != pj.InboxProject {
+			return pi.InboxProject
+		}
+		return pi.ChildOrder < pj.ChildOrder
+	})
+}
+
+// Children returns the direct sub projects of the project ordered by ChildOrder.
+func (c ProjectClient) Children(id ID) []Project {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.children(c.tempIDs.Resolve(id))
+}
+
+// Ancestors returns the parents of the project from the root to the direct parent.
+func (c ProjectClient) Ancestors(id ID) []Project {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []Project
+	seen := map[ID]bool{}
+	project, ok := c.cache.projects[c.tempIDs.Resolve(id)]
+	for ok && !project.ParentID.IsZero() && !seen[project.ParentID] {
+		seen[project.ParentID] = true
+		if project, ok = c.cache.projects[project.ParentID]; ok {
+			res = append([]Project{project}, res...)
+		}
+	}
+	return res
+}
+
+// Path returns the projects from the root to the project, or nil if the project is not cached.
+func (c ProjectClient) Path(id ID) []Project {
+	project := c.Resolve(id)
+	if project == nil {
+		return nil
+	}
+	return append(c.Ancestors(id), *project)
+}
+
+// PathString returns the path of the project like "#Work/Backend/API".
+func (c ProjectClient) PathString(id ID) string {
+	var names []string
+	for _, p := range c.Path(id) {
+		names = append(names, p.Name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(names, "/")
+}
+
+// Descendants returns all the sub projects of the project in depth-first order.
+func (c ProjectClient) Descendants(id ID) []Project {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []Project
+	seen := map[ID]bool{}
+	var walk func(id ID)
+	walk = func(id ID) {
+		for _, child := range c.cache.children(id) {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			res = append(res, child)
+			walk(child.ID)
+		}
+	}
+	walk(c.tempIDs.Resolve(id))
+	return res
+}
+
+// Forest returns the trees of all the cached projects.
+func (c ProjectClient) Forest() []*ProjectNode {
+	return BuildProjectForest(c.GetAll())
+}
+
+func (c *projectCache) children(id ID) []Project {
+	children := c.find(c.byParent, []ID{id})
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].ChildOrder < children[j].ChildOrder
+	})
+	return children
+}
+
+// ProjectPathError describes a project path which does not resolve to a single project.
+type ProjectPathError struct {
+	Path string
+	// Missing is the path to the first segment which does not exist.
+	Missing string
+	// Candidates are the paths of the projects matching an ambiguous path.
+	Candidates []string
+}
+
+func (e *ProjectPathError) Error() string {
+	if len(e.Candidates) != 0 {
+		return fmt.Sprintf("ambiguous project %s: %s", e.Path, strings.Join(e.Candidates, ", "))
+	}
+	return fmt.Sprintf("no such project: %s", e.Missing)
+}
+
+// ResolvePath resolves the project of the slash separated path like "#Work/Backend/API".
+// The first segment matches a project at any level, and the following ones match its sub projects.
+// Names are compared exactly, or case-insensitively if no project matches exactly.
+func (c ProjectClient) ResolvePath(path string) (*Project, error) {
+	trimmed := strings.TrimPrefix(path, "#")
+	segments := strings.Split(trimmed, "/")
+	for _, segment := range segments {
+		if len(segment) == 0 {
+			return nil, fmt.Errorf("invalid project path: %s", path)
+		}
+	}
+	c.mu.RLock()
+	candidates := matchProjectName(c.cache.getAll(), segments[0])
+	for i, segment := range segments[1:] {
+		var children []Project
+		for _, p := range candidates {
+			children = append(children, c.cache.children(p.ID)...)
+		}
+		if candidates = matchProjectName(children, segment); len(candidates) == 0 {
+			c.mu.RUnlock()
+			return nil, &ProjectPathError{Path: path, Missing: "#" + strings.Join(segments[:i+2], "/")}
+		}
+	}
+	c.mu.RUnlock()
+	switch len(candidates) {
+	case 0:
+		return nil, &ProjectPathError{Path: path, Missing: "#" + segments[0]}
+	case 1:
+		return &candidates[0], nil
+	}
+	var paths []string
+	for _, p := range candidates {
+		paths = append(paths, c.PathString(p.ID))
+	}
+	return nil, &ProjectPathError{Path: path, Candidates: paths}
+}
+
+func matchProjectName(projects []Project, name string) []Project {
+	var res []Project
+	for _, p := range projects {
+		if p.Name == name {
+			res = append(res, p)
+		}
+	}
+	if len(res) != 0 {
+		return res
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, name) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
@@ -0,0 +1,115 @@
+package todoist
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestProjectTree(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	project := func(id, parentID ID, name string, order int) Project {
+		p := Project{Name: name, ParentID: parentID, ChildOrder: order}
+		p.ID = id
+		return p
+	}
+	// children are listed before their parents.
+	projects := []Project{
+		project("4", "2", "API", 1),
+		project("3", "1", "Frontend", 2),
+		project("2", "1", "Backend", 1),
+		project("1", "", "Work", 2),
+		project("5", "", "Home", 1),
+	}
+	c.updateState(&SyncState{FullSync: true, Projects: projects})
+
+	ids := func(projects []Project) []ID {
+		var res []ID
+		for _, p := range projects {
+			res = append(res, p.ID)
+		}
+		return res
+	}
+	tests := []struct {
+		name   string
+		actual []Project
+		expect []ID
+	}{
+		{"children", c.Project.Children("1"), []ID{"2", "3"}},
+		{"ancestors", c.Project.Ancestors("4"), []ID{"1", "2"}},
+		{"path", c.Project.Path("4"), []ID{"1", "2", "4"}},
+		{"path of unknown", c.Project.Path("9"), nil},
+		{"descendants", c.Project.Descendants("1"), []ID{"2", "4", "3"}},
+	}
+	for _, test := range tests {
+		if actual := ids(test.actual); !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("%s: Expect %v, but got %v", test.name, test.expect, actual)
+		}
+	}
+	if path := c.Project.PathString("4"); path != "#Work/Backend/API" {
+		t.Errorf("Expect %s, but got %s", "#Work/Backend/API", path)
+	}
+
+	var walked []ID
+	var depths []int
+	for _, root := range c.Project.Forest() {
+		root.Walk(func(node *ProjectNode) bool {
+			walked = append(walked, node.Project.ID)
+			depths = append(depths, node.Depth)
+			return true
+		})
+	}
+	if expect := []ID{"5", "1", "2", "4", "3"}; !reflect.DeepEqual(walked, expect) {
+		t.Errorf("Expect %v, but got %v", expect, walked)
+	}
+	if expect := []int{0, 0, 1, 2, 1}; !reflect.DeepEqual(depths, expect) {
+		t.Errorf("Expect %v, but got %v", expect, depths)
+	}
+}
+
+func TestProjectClient_ResolvePath(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	project := func(id, parentID ID, name string) Project {
+		p := Project{Name: name, ParentID: parentID}
+		p.ID = id
+		return p
+	}
+	c.updateState(&SyncState{FullSync: true, Projects: []Project{
+		project("1", "", "Work"),
+		project("2", "1", "Backend"),
+		project("3", "2", "API"),
+		project("4", "", "Home"),
+		project("5", "4", "Backend"),
+		project("6", "", "Inbox"),
+	}})
+	tests := []struct {
+		path   string
+		expect ID
+		err    string
+	}{
+		{"#Work/Backend/API", "3", ""},
+		{"Work/Backend", "2", ""},
+		{"#Home/Backend", "5", ""},
+		{"Backend/API", "3", ""},
+		{"API", "3", ""},
+		{"inbox", "6", ""},
+		{"#Backend", "", "ambiguous project #Backend: #Work/Backend, #Home/Backend"},
+		{"#Work/Frontend/API", "", "no such project: #Work/Frontend"},
+		{"#Office", "", "no such project: #Office"},
+		{"#Work//API", "", "invalid project path: #Work//API"},
+	}
+	for _, test := range tests {
+		p, err := c.Project.ResolvePath(test.path)
+		if len(test.err) != 0 {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: Expect %s, but got %v", test.path, test.err, err)
+			}
+			continue
+		}
+		if err != nil || p.ID != test.expect {
+			t.Errorf("%s: Expect %s, but got %v (%v)", test.path, test.expect, p, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package todoist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Queue returns the commands which are not sent yet.
+func (c *Client) Queue() []Command {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Command{}, c.queue...)
+}
+
+// DropCommand removes the command from the queue and saves the queue.
+// A resource added optimistically by the command is removed from the cache too.
+func (c *Client) DropCommand(uuid UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var res []Command
+	found := false
+	for _, command := range c.queue {
+		if command.UUID == uuid {
+			found = true
+			if !command.TempID.IsZero() {
+				c.discardTempID(command.TempID)
+			}
+			continue
+		}
+		res = append(res, command)
+	}
+	if !found {
+		return fmt.Errorf("no such command in the queue: %s", uuid)
+	}
+	c.queue = res
+	return c.writeCache()
+}
+
+// SaveQueue saves the queued commands and the cached state,
+// so that the commands can be replayed by Commit after the process restarts.
+func (c *Client) SaveQueue() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writeCache()
+}
+
+func (c *Client) readQueue() error {
+	if c.Storage == nil {
+		return nil
+	}
+	queue, err := c.loadQueue()
+	if err != nil {
+		return err
+	}
+	c.queue = queue
+	c.queueBase = commandSet(queue)
+	return nil
+}
+
+func (c *Client) loadQueue() ([]Command, error) {
+	b, err := c.Storage.Load(c.queueKey())
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// keep numbers as is, because args of commands are decoded into generic values.
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var queue []Command
+	if err = decoder.Decode(&queue); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// writeQueue merges the queue with the stored one and saves it. It must be called with the storage locked.
+// Other processes may have sent or queued commands since the queue was read or written last.
+// Commands missing in the stored queue are sent by them, so they are removed from the queue
+// with the resources added by them, and commands new in the stored queue are appended to the queue.
+func (c *Client) writeQueue() error {
+	if c.Storage == nil {
+		return nil
+	}
+	stored, err := c.loadQueue()
+	if err != nil {
+		return err
+	}
+	storedSet := commandSet(stored)
+	ownSet := commandSet(c.queue)
+	var queue []Command
+	for _, command := range c.queue {
+		_, inBase := c.queueBase[command.UUID]
+		_, inStored := storedSet[command.UUID]
+		if inBase && !inStored {
+			if !command.TempID.IsZero() {
+				c.discardTempID(command.TempID)
+			}
+			continue
+		}
+		queue = append(queue, command)
+	}
+	for _, command := range stored {
+		_, inBase := c.queueBase[command.UUID]
+		_, inOwn := ownSet[command.UUID]
+		if !inBase && !inOwn {
+			queue = append(queue, command)
+		}
+	}
+	c.queue = queue
+	c.queueBase = commandSet(queue)
+	if len(queue) == 0 {
+		return c.Storage.Delete(c.queueKey())
+	}
+	b, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return err
+	}
+	return c.Storage.Save(c.queueKey(), b)
+}
+
+func commandSet(commands []Command) map[UUID]struct{} {
+	res := map[UUID]struct{}{}
+	for _, command := range commands {
+		res[command.UUID] = struct{}{}
+	}
+	return res
+}
@@ -0,0 +1,108 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestClient_persistQueue(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+	c.Retry = RetryPolicy{}
+
+	item, _ := NewItem("foo", &NewItemOpts{ProjectID: ID("1000000")})
+	c.Item.Add(*item)
+	if err := c.Commit(context.Background()); err == nil {
+		t.Fatal("Expect error, but no error")
+	}
+
+	restored, err := NewClient(ts.URL, c.Token, "*", c.CacheDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := restored.Queue()
+	if len(queue) != 1 || queue[0].UUID != c.queue[0].UUID || queue[0].TempID != item.ID {
+		t.Fatalf("Unexpect queue: %#v", queue)
+	}
+	args := queue[0].Args.(map[string]interface{})
+	if args["project_id"] != json.Number("1000000") {
+		t.Errorf("Expect %s, but got %v", "1000000", args["project_id"])
+	}
+	if i := restored.Item.Resolve(item.ID); i == nil || i.Content != "foo" {
+		t.Errorf("Expect cached item, but got %v", i)
+	}
+
+	if err = restored.DropCommand(queue[0].UUID); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if i := restored.Item.Resolve(item.ID); i != nil {
+		t.Errorf("Expect nil, but got %v", i)
+	}
+	restored, err = NewClient(ts.URL, c.Token, "*", c.CacheDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(restored.Queue()); n != 0 {
+		t.Errorf("Expect %d, but got %d", 0, n)
+	}
+}
+
+func TestClient_mergeQueue(t *testing.T) {
+	a := newTestClient(t, "")
+	defer os.RemoveAll(a.CacheDir)
+	b, err := NewClient("", a.Token, "*", a.CacheDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uuids := func(c *Client) []UUID {
+		var res []UUID
+		for _, command := range c.Queue() {
+			res = append(res, command.UUID)
+		}
+		return res
+	}
+
+	foo, _ := NewItem("foo", &NewItemOpts{ProjectID: ID("1000000")})
+	a.Item.Add(*foo)
+	if err = a.SaveQueue(); err != nil {
+		t.Fatal(err)
+	}
+	bar, _ := NewItem("bar", &NewItemOpts{ProjectID: ID("1000000")})
+	b.Item.Add(*bar)
+	if err = b.SaveQueue(); err != nil {
+		t.Fatal(err)
+	}
+	fooUUID, barUUID := a.Queue()[0].UUID, b.Queue()[0].UUID
+	if expect := []UUID{barUUID, fooUUID}; !reflect.DeepEqual(uuids(b), expect) {
+		t.Errorf("Expect %v, but got %v", expect, uuids(b))
+	}
+	if err = a.SaveQueue(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []UUID{fooUUID, barUUID}; !reflect.DeepEqual(uuids(a), expect) {
+		t.Errorf("Expect %v, but got %v", expect, uuids(a))
+	}
+
+	// a command dropped by b is removed from a with the item added by it
+	if err = b.DropCommand(fooUUID); err != nil {
+		t.Fatal(err)
+	}
+	if err = a.SaveQueue(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []UUID{barUUID}; !reflect.DeepEqual(uuids(a), expect) {
+		t.Errorf("Expect %v, but got %v", expect, uuids(a))
+	}
+	if i := a.Item.Resolve(foo.ID); i != nil {
+		t.Errorf("Expect nil, but got %v", i)
+	}
+}
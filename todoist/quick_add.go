@@ -0,0 +1,282 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// UnknownNameError is returned when a project, section or label in the quick add text does not exist.
+type UnknownNameError struct {
+	// Token is the name with its prefix, e.g. "#Hoem".
+	Token string
+	// Suggestions are the tokens of similar names.
+	Suggestions []string
+}
+
+func (e *UnknownNameError) Error() string {
+	kind := map[byte]string{'#': "project", '/': "section", '@': "label"}[e.Token[0]]
+	msg := fmt.Sprintf("no such %s: %s", kind, e.Token)
+	if len(e.Suggestions) != 0 {
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(e.Suggestions, " or "))
+	}
+	return msg
+}
+
+// ParseQuickAdd parses the text of the quick add syntax like "Pay rent #Home @finance p1 every 1st",
+// and returns the content and the options of the item.
+// Projects ("#Work/Backend" as well), sections ("/Meetings") and labels are resolved by the cache,
+// "p1" to "p4" are priorities, and the longest words which can be parsed by ParseDue are the due date,
+// except for abbreviations like "tom" which may be words of the content.
+// Project names may contain spaces, e.g. "#Home Office".
+func (c *ItemClient) ParseQuickAdd(text string) (string, *NewItemOpts, error) {
+	var lang string
+	if user := c.User.Get(); user != nil {
+		lang = user.Lang
+	}
+	if _, ok := dueRules[lang]; !ok {
+		// detect due dates in English at least.
+		lang = ""
+	}
+	opts := NewItemOpts{}
+	words := strings.Fields(text)
+	var sectionName string
+	var rest []string
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case len(word) > 1 && word[0] == '#':
+			project, n, err := c.quickAddProject(words[i:])
+			if err != nil {
+				return "", nil, err
+			}
+			opts.ProjectID = project.ID
+			i += n - 1
+		case len(word) > 1 && word[0] == '/':
+			sectionName = word[1:]
+		case len(word) > 1 && word[0] == '@':
+			label, err := c.quickAddLabel(word[1:])
+			if err != nil {
+				return "", nil, err
+			}
+			opts.Labels = append(opts.Labels, label.ID)
+		case len(word) == 2 && (word[0] == 'p' || word[0] == 'P') && '1' <= word[1] && word[1] <= '4':
+			// p1 is the highest priority, which is 4 in the api.
+			opts.Priority = 5 - int(word[1]-'0')
+		default:
+			rest = append(rest, word)
+		}
+	}
+	if len(sectionName) != 0 {
+		section, err := c.quickAddSection(opts.ProjectID, sectionName)
+		if err != nil {
+			return "", nil, err
+		}
+		opts.SectionID = section.ID
+	}
+	if due, from, to := quickAddDue(rest, lang, c.Location()); due != nil {
+		opts.Due = *due
+		rest = append(rest[:from:from], rest[to:]...)
+	}
+	content := strings.Join(rest, " ")
+	if len(content) == 0 {
+		return "", nil, fmt.Errorf("no content in quick add: %s", text)
+	}
+	return content, &opts, nil
+}
+
+// quickAddProject resolves the project of the longest name starting at the first word,
+// and returns the number of the words of the name.
+func (c *ItemClient) quickAddProject(words []string) (*Project, int, error) {
+	for n := len(words); n > 1; n-- {
+		if strings.ContainsAny(words[n-1][:1], "#/@") {
+			continue
+		}
+		if project, err := c.Project.ResolvePath(strings.Join(words[:n], " ")); err == nil {
+			return project, n, nil
+		}
+	}
+	project, err := c.Project.ResolvePath(words[0])
+	if err == nil {
+		return project, 1, nil
+	}
+	if perr, ok := err.(*ProjectPathError); ok && len(perr.Candidates) == 0 {
+		var names []string
+		for _, p := range c.Project.GetAll() {
+			names = append(names, p.Name)
+		}
+		return nil, 0, &UnknownNameError{Token: words[0], Suggestions: suggestNames(words[0], "#", names)}
+	}
+	return nil, 0, err
+}
+
+func (c *ItemClient) quickAddLabel(name string) (*Label, error) {
+	var names []string
+	for _, label := range c.Label.GetAll() {
+		if strings.EqualFold(label.Name, name) {
+			return &label, nil
+		}
+		names = append(names, label.Name)
+	}
+	return nil, &UnknownNameError{Token: "@" + name, Suggestions: suggestNames("@"+name, "@", names)}
+}
+
+func (c *ItemClient) quickAddSection(projectID ID, name string) (*Section, error) {
+	sections := c.Section.GetAll()
+	if !projectID.IsZero() {
+		sections = c.Section.FindByProjectID(projectID)
+	}
+	var names []string
+	for _, section := range sections {
+		if strings.EqualFold(section.Name, name) {
+			return &section, nil
+		}
+		names = append(names, section.Name)
+	}
+	return nil, &UnknownNameError{Token: "/" + name, Suggestions: suggestNames("/"+name, "/", names)}
+}
+
+// ambiguousDueWords are abbreviations in due dates which are common words too, like "tom" and "sun".
+var ambiguousDueWords = map[string]struct{}{
+	"tod": {}, "tom": {}, "tmr": {},
+	"sun": {}, "mon": {}, "tue": {}, "wed": {}, "thu": {}, "fri": {}, "sat": {},
+	"jan": {}, "feb": {}, "mar": {}, "apr": {}, "may": {}, "jun": {},
+	"jul": {}, "aug": {}, "sep": {}, "oct": {}, "nov": {}, "dec": {},
+}
+
+// quickAddDue finds the longest words which can be parsed as a due date in the timezone, preferring the last ones.
+// It returns the due date and the range of the words.
+// Words which are just abbreviations are taken only at the end of the text if no other words are a due date,
+// e.g. "tom" in "Email tom about it" is a name, but "tomorrow" is a date anywhere.
+func quickAddDue(words []string, lang string, loc *time.Location) (*Due, int, int) {
+	for n := len(words); n > 0; n-- {
+		for from := len(words) - n; from >= 0; from-- {
+			if isAmbiguousDue(words[from : from+n]) {
+				continue
+			}
+			if due, err := ParseDue(strings.Join(words[from:from+n], " "), lang, loc); err == nil {
+				return due, from, from + n
+			}
+		}
+	}
+	for from := 0; from < len(words); from++ {
+		if !isAmbiguousDue(words[from:]) {
+			continue
+		}
+		if due, err := ParseDue(strings.Join(words[from:], " "), lang, loc); err == nil {
+			return due, from, len(words)
+		}
+	}
+	return nil, 0, 0
+}
+
+func isAmbiguousDue(words []string) bool {
+	for _, word := range words {
+		if _, ok := ambiguousDueWords[strings.ToLower(strings.TrimRight(word, ".,"))]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// suggestNames returns the tokens of the names similar to the token, the most similar first.
+func suggestNames(token, prefix string, names []string) []string {
+	name := strings.ToLower(strings.TrimPrefix(token, prefix))
+	type candidate struct {
+		token    string
+		distance int
+	}
+	var candidates []candidate
+	seen := map[string]bool{}
+	for _, n := range names {
+		lower := strings.ToLower(n)
+		d := editDistance(name, lower)
+		// allow a typo in every 3 characters, or a part of the name.
+		if d > (utf8.RuneCountInString(lower)+2)/3 && !strings.Contains(lower, name) {
+			continue
+		}
+		t := prefix + n
+		if !seen[t] {
+			seen[t] = true
+			candidates = append(candidates, candidate{t, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	var res []string
+	for i, c := range candidates {
+		if i == 3 {
+			break
+		}
+		res = append(res, c.token)
+	}
+	return res
+}
+
+// editDistance returns the Levenshtein distance between the strings.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur := make([]int, len(t)+1)
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(t)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// QuickAdd adds the item by the quick add endpoint, which parses the text on the server like the official apps.
+// It requires to be online, and the added item is stored in the cache.
+func (c *ItemClient) QuickAdd(ctx context.Context, text string) (*Item, error) {
+	values := url.Values{"text": {text}}
+	req, err := c.newRequest(ctx, http.MethodPost, "quick/add", values)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if (res.StatusCode / 100) != 2 {
+		res.Body.Close()
+		return nil, &StatusError{Op: "quick add", StatusCode: res.StatusCode}
+	}
+	var item Item
+	if err = decodeBody(res, &item); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item.Due.Date = item.Due.Date.localize(c.location)
+	c.cache.store(item)
+	if err = c.writeCache(); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
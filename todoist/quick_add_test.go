@@ -0,0 +1,121 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestItemClient_ParseQuickAdd(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	project := func(id, parentID ID, name string) Project {
+		p := Project{Name: name, ParentID: parentID}
+		p.ID = id
+		return p
+	}
+	label := func(id ID, name string) Label {
+		l := Label{Name: name}
+		l.ID = id
+		return l
+	}
+	section := func(id, projectID ID, name string) Section {
+		s := Section{Name: name, ProjectID: projectID}
+		s.ID = id
+		return s
+	}
+	c.updateState(&SyncState{
+		FullSync: true,
+		User:     &User{TzInfo: TzInfo{Timezone: "UTC"}},
+		Projects: []Project{project("1", "", "Home"), project("2", "", "Home Office"), project("3", "", "Work"), project("4", "3", "Backend")},
+		Labels:   []Label{label("10", "finance"), label("20", "errand")},
+		Sections: []Section{section("30", "3", "Meetings")},
+	})
+
+	tests := []struct {
+		text      string
+		content   string
+		projectID ID
+		sectionID ID
+		labels    []ID
+		priority  int
+		due       string
+	}{
+		{"Pay rent #Home @finance p1 every 1st", "Pay rent", "1", "", []ID{"10"}, 4, "every 1st"},
+		{"Buy a chair #Home Office", "Buy a chair", "2", "", nil, 0, ""},
+		{"Fix bug #Work/Backend p3 tomorrow 5pm", "Fix bug", "4", "", nil, 2, "tomorrow 5pm"},
+		{"Weekly sync #work /meetings every mon", "Weekly sync", "3", "30", nil, 0, "every mon"},
+		{"Post @errand @finance letters", "Post letters", "", "", []ID{"20", "10"}, 0, ""},
+		{"Call mom next fri at 9am", "Call mom", "", "", nil, 0, "next fri at 9am"},
+		{"Email tom about sun screen #Home", "Email tom about sun screen", "1", "", nil, 0, ""},
+		{"Email tom tomorrow about it", "Email tom about it", "", "", nil, 0, "tomorrow"},
+		{"Go out on sunday with tom", "Go out on with tom", "", "", nil, 0, "sunday"},
+		{"Buy sunscreen tom", "Buy sunscreen", "", "", nil, 0, "tom"},
+	}
+	for _, test := range tests {
+		content, opts, err := c.Item.ParseQuickAdd(test.text)
+		if err != nil {
+			t.Errorf("%s: Unexpect error: %s", test.text, err)
+			continue
+		}
+		if content != test.content {
+			t.Errorf("%s: Expect %s, but got %s", test.text, test.content, content)
+		}
+		if opts.ProjectID != test.projectID || opts.SectionID != test.sectionID || opts.Priority != test.priority {
+			t.Errorf("%s: Expect %s, %s, %d, but got %s, %s, %d",
+				test.text, test.projectID, test.sectionID, test.priority, opts.ProjectID, opts.SectionID, opts.Priority)
+		}
+		if !reflect.DeepEqual(opts.Labels, test.labels) {
+			t.Errorf("%s: Expect %v, but got %v", test.text, test.labels, opts.Labels)
+		}
+		if opts.Due.String != test.due || (len(test.due) != 0) == opts.Due.Date.IsZero() {
+			t.Errorf("%s: Expect %s, but got %+v", test.text, test.due, opts.Due)
+		}
+	}
+
+	errors := []struct {
+		text string
+		err  string
+	}{
+		{"Pay rent #Hme", "no such project: #Hme, did you mean #Home?"},
+		{"Pay rent @fnance", "no such label: @fnance, did you mean @finance?"},
+		{"Pay rent #Home /meeting", "no such section: /meeting"},
+		{"Pay rent @foo", "no such label: @foo"},
+		{"#Home p1 tomorrow", "no content in quick add: #Home p1 tomorrow"},
+	}
+	for _, test := range errors {
+		if _, _, err := c.Item.ParseQuickAdd(test.text); err == nil || err.Error() != test.err {
+			t.Errorf("%s: Expect %s, but got %v", test.text, test.err, err)
+		}
+	}
+}
+
+func TestItemClient_QuickAdd(t *testing.T) {
+	var text string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/quick/add" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		text = r.FormValue("text")
+		fmt.Fprint(w, `{"id": 1, "project_id": 2, "content": "Pay rent", "priority": 4}`)
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	defer os.RemoveAll(c.CacheDir)
+
+	item, err := c.Item.QuickAdd(context.Background(), "Pay rent #Home p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Pay rent #Home p1" {
+		t.Errorf("Expect %s, but got %s", "Pay rent #Home p1", text)
+	}
+	if item.Content != "Pay rent" || c.Item.Resolve("1") == nil {
+		t.Errorf("Expect the added item cached, but got %v", item)
+	}
+}
@@ -0,0 +1,438 @@
+package todoist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Recurrence is the rule of a recurring due date like "every monday" or "every! 3 days".
+type Recurrence struct {
+	// Unit is "day", "week", "month" or "year", and Interval is the number of units between occurrences.
+	Unit     string
+	Interval int
+	// Weekdays are the days of the week on which it occurs, e.g. "every mon, fri" or "every workday".
+	Weekdays []time.Weekday
+	// Ordinal is the week of the month of Weekdays, e.g. 3 of "every 3rd friday", or -1 for the last week.
+	Ordinal int
+	// Month and Day are the day of the year, or Day is the day of the month if Month is zero.
+	Month time.Month
+	Day   int
+	// FromCompletion is true for "every!", which next occurrence is counted from the completion.
+	FromCompletion bool
+	HasTime        bool
+	Hour           int
+	Minute         int
+}
+
+// ParseRecurrence parses the recurring due date written in the natural language of lang,
+// like "every monday", "every 2 weeks", "every workday", "every 3rd friday" or "every! 3 days" in English,
+// and "毎週月曜" or "毎月第3金曜" in Japanese. English is used if lang is empty.
+func ParseRecurrence(value, lang string) (*Recurrence, error) {
+	if len(lang) == 0 {
+		lang = "en"
+	}
+	rules, ok := dueRules[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language of due date: %s", lang)
+	}
+	r, rest, err := parseRecurrence(normalizeDue(value), lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurring due date %s: %s", value, err)
+	}
+	if r == nil {
+		return nil, fmt.Errorf("not a recurring due date: %s", value)
+	}
+	var s dueState
+	if err = s.parse(rest, rules); err != nil {
+		return nil, fmt.Errorf("invalid recurring due date %s: %s", value, err)
+	}
+	if s.hasDate {
+		return nil, fmt.Errorf("invalid recurring due date %s: recurring due date with a date is not supported", value)
+	}
+	r.HasTime, r.Hour, r.Minute = s.hasTime, s.hour, s.minute
+	return r, nil
+}
+
+// Next returns the first occurrence after the time, or zero time if it never occurs.
+// The time is usually the current occurrence, which the interval is counted from,
+// e.g. the next occurrence of "every 2 weeks" is 2 weeks after it.
+// The clock of the time is kept unless the recurrence has its own time.
+// Occurrences are in the timezone loc, which is usually Client.Location.
+func (r Recurrence) Next(from Time, loc *time.Location) Time {
+	loc = wallLocation(loc)
+	t := from.In(loc).Time
+	hasClock := !from.In(loc).IsFullDay()
+	return r.search(civilDate(t), t, hasClock, loc, func(next time.Time) bool {
+		return next.After(t)
+	})
+}
+
+// NextN returns the n occurrences after the time.
+func (r Recurrence) NextN(from Time, n int, loc *time.Location) []Time {
+	var res []Time
+	for i := 0; i < n; i++ {
+		if from = r.Next(from, loc); from.IsZero() {
+			break
+		}
+		res = append(res, from)
+	}
+	return res
+}
+
+// first returns the first occurrence from today, which is not before now if it has time.
+// It occurs in the timezone of now.
+func (r Recurrence) first(now time.Time) Time {
+	return r.search(civilDate(now), now, false, now.Location(), func(next time.Time) bool {
+		return !r.HasTime || !next.Before(now)
+	})
+}
+
+// search returns the first occurrence from the anchor date which is ok.
+func (r Recurrence) search(anchor, clock time.Time, hasClock bool, loc *time.Location, ok func(next time.Time) bool) Time {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	// long enough to find the 29th of February in the interval.
+	limit := 4*366*interval + 31
+	for i := 0; i < limit; i++ {
+		d := anchor.AddDate(0, 0, i)
+		if !r.matches(d, anchor, interval) {
+			continue
+		}
+		next := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+		if r.HasTime {
+			next = time.Date(d.Year(), d.Month(), d.Day(), r.Hour, r.Minute, 0, 0, loc)
+		} else if hasClock {
+			next = time.Date(d.Year(), d.Month(), d.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+		}
+		if ok(next) {
+			return Time{next}
+		}
+	}
+	return Time{}
+}
+
+// matches reports whether it occurs on the date d, which unit is counted from the anchor date.
+func (r Recurrence) matches(d, anchor time.Time, interval int) bool {
+	switch {
+	case len(r.Weekdays) != 0 && r.Ordinal > 0:
+		if (d.Day()-1)/7+1 != r.Ordinal || !r.onWeekday(d) {
+			return false
+		}
+	case len(r.Weekdays) != 0 && r.Ordinal < 0:
+		if d.AddDate(0, 0, 7).Month() == d.Month() || !r.onWeekday(d) {
+			return false
+		}
+	case len(r.Weekdays) != 0:
+		if !r.onWeekday(d) {
+			return false
+		}
+	case r.Month != 0:
+		if d.Month() != r.Month || d.Day() != clampDay(d, r.Day) {
+			return false
+		}
+	case r.Day != 0:
+		if d.Day() != clampDay(d, r.Day) {
+			return false
+		}
+	case r.Unit == "week":
+		if d.Weekday() != anchor.Weekday() {
+			return false
+		}
+	case r.Unit == "month":
+		if d.Day() != clampDay(d, anchor.Day()) {
+			return false
+		}
+	case r.Unit == "year":
+		if d.Month() != anchor.Month() || d.Day() != clampDay(d, anchor.Day()) {
+			return false
+		}
+	}
+	var n int
+	switch r.Unit {
+	case "day":
+		n = int(d.Sub(anchor).Hours() / 24)
+	case "week":
+		// weeks start on Monday.
+		n = int(weekStart(d).Sub(weekStart(anchor)).Hours() / 24 / 7)
+	case "month":
+		n = (d.Year()-anchor.Year())*12 + int(d.Month()-anchor.Month())
+	case "year":
+		n = d.Year() - anchor.Year()
+	}
+	return n%interval == 0
+}
+
+func (r Recurrence) onWeekday(d time.Time) bool {
+	for _, weekday := range r.Weekdays {
+		if d.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// civilDate returns the date of the time at midnight in UTC, so that days can be counted without timezones.
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func weekStart(d time.Time) time.Time {
+	return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+}
+
+// clampDay returns the day, or the last day of the month of d if the month is shorter.
+func clampDay(d time.Time, day int) int {
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		return last
+	}
+	return day
+}
+
+// Recurrence returns the recurrence of the recurring due date.
+func (d Due) Recurrence() (*Recurrence, error) {
+	if !d.IsRecurring {
+		return nil, fmt.Errorf("not a recurring due date: %s", d.String)
+	}
+	return ParseRecurrence(d.String, d.Lang)
+}
+
+// Next returns the due date moved to the next occurrence as it is completed at the time.
+// Occurrences which have passed at the time are skipped,
+// and the next occurrence of "every!" is counted from the time.
+// Occurrences are in the timezone loc, which is usually Client.Location.
+func (d Due) Next(completed Time, loc *time.Location) (*Due, error) {
+	loc = wallLocation(loc)
+	r, err := d.Recurrence()
+	if err != nil {
+		return nil, err
+	}
+	var next Time
+	if r.FromCompletion {
+		from := completed.In(loc)
+		if due := d.Date.In(loc); !r.HasTime && !due.IsFullDay() {
+			// keep the clock of the due date.
+			from = Time{time.Date(from.Year(), from.Month(), from.Day(), due.Hour(), due.Minute(), due.Second(), 0, loc)}
+		} else {
+			from = Time{time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)}
+		}
+		next = r.Next(from, loc)
+	} else {
+		next = r.Next(d.Date, loc)
+		for !next.IsZero() && passed(next, completed, loc) {
+			next = r.Next(next, loc)
+		}
+	}
+	if next.IsZero() {
+		return nil, fmt.Errorf("recurring due date never occurs again: %s", d.String)
+	}
+	due := d
+	due.Date = next
+	if d.Date.Location() == time.UTC {
+		due.Date = Time{next.UTC()}
+	}
+	return &due, nil
+}
+
+// Occurrences returns the n occurrences after the due date.
+func (d Due) Occurrences(n int, loc *time.Location) ([]Time, error) {
+	r, err := d.Recurrence()
+	if err != nil {
+		return nil, err
+	}
+	return r.NextN(d.Date, n, loc), nil
+}
+
+// passed reports whether the occurrence has passed at the time.
+// Full-day occurrences pass at the end of the day.
+func passed(occurrence, at Time, loc *time.Location) bool {
+	if occurrence.In(loc).IsFullDay() {
+		return civilDate(occurrence.In(loc).Time).Before(civilDate(at.In(loc).Time))
+	}
+	return occurrence.Before(at)
+}
+
+var (
+	recurrenceUnits = map[string]string{
+		"day": "day", "week": "week", "month": "month", "year": "year",
+		"日": "day", "週間": "week", "ヶ月": "month", "か月": "month", "ヵ月": "month", "カ月": "month", "年": "year",
+	}
+	recurrenceAdverbs = map[string]string{
+		"daily": "day", "weekly": "week", "monthly": "month", "yearly": "year", "annually": "year",
+	}
+	workdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	weekends = []time.Weekday{time.Saturday, time.Sunday}
+)
+
+var (
+	englishRecurrencePrefix     = regexp.MustCompile(`^(?:every(!?)\s+|(daily|weekly|monthly|yearly|annually)\b)`)
+	englishRecurrenceInterval   = regexp.MustCompile(`^(other|\d+)?\s*(day|week|month|year)s?\b`)
+	englishRecurrenceWorkday    = regexp.MustCompile(`^(?:workday|weekday)s?\b`)
+	englishRecurrenceWeekend    = regexp.MustCompile(`^weekends?\b`)
+	englishRecurrenceOrdinal    = regexp.MustCompile(`^(?:(\d)(?:st|nd|rd|th)|(last))\s+` + englishWeekdayPattern + `\b`)
+	englishRecurrenceWeekday    = regexp.MustCompile(`^(other\s+)?` + englishWeekdayPattern + `\b`)
+	englishRecurrenceMoreDay    = regexp.MustCompile(`^\s*(?:,|and)\s*` + englishWeekdayPattern + `\b`)
+	englishRecurrenceDayOfYear1 = regexp.MustCompile(`^` + englishMonthPattern + `\s*(\d{1,2})(?:st|nd|rd|th)?\b`)
+	englishRecurrenceDayOfYear2 = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?\s+` + englishMonthPattern + `\b`)
+	englishRecurrenceDay        = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)\b`)
+
+	japaneseRecurrencePrefix    = regexp.MustCompile(`^(?:毎(日|週|月|年)|毎?平日|隔週|(\d+)\s*(日|週間|ヶ月|か月|ヵ月|カ月|年)(?:ごと|毎)に?)`)
+	japaneseRecurrenceWeekday   = regexp.MustCompile(`^([日月火水木金土])曜日?`)
+	japaneseRecurrenceMoreDay   = regexp.MustCompile(`^[、・と,]\s*([日月火水木金土])曜日?`)
+	japaneseRecurrenceOrdinal   = regexp.MustCompile(`^(?:第(\d)|(最終))([日月火水木金土])曜日?`)
+	japaneseRecurrenceDay       = regexp.MustCompile(`^(\d{1,2})日`)
+	japaneseRecurrenceDayOfYear = regexp.MustCompile(`^(\d{1,2})月(\d{1,2})日`)
+)
+
+// parseRecurrence parses the recurrence at the head of the normalized value, and returns the rest of it.
+// The recurrence is nil if the value is not recurring.
+func parseRecurrence(value, lang string) (*Recurrence, string, error) {
+	switch lang {
+	case "en":
+		return parseEnglishRecurrence(value)
+	case "ja":
+		return parseJapaneseRecurrence(value)
+	}
+	return nil, value, nil
+}
+
+func parseEnglishRecurrence(value string) (*Recurrence, string, error) {
+	m := englishRecurrencePrefix.FindStringSubmatch(value)
+	if m == nil {
+		return nil, value, nil
+	}
+	rest := value[len(m[0]):]
+	r := &Recurrence{Interval: 1, FromCompletion: m[1] == "!"}
+	if len(m[2]) != 0 {
+		r.Unit = recurrenceAdverbs[m[2]]
+		return r, rest, nil
+	}
+	if m = englishRecurrenceInterval.FindStringSubmatch(rest); m != nil {
+		r.Unit = recurrenceUnits[m[2]]
+		switch m[1] {
+		case "":
+		case "other":
+			r.Interval = 2
+		default:
+			if r.Interval = atoi(m[1]); r.Interval < 1 {
+				return nil, value, fmt.Errorf("invalid interval %s", m[1])
+			}
+		}
+	} else if m = englishRecurrenceWorkday.FindStringSubmatch(rest); m != nil {
+		r.Unit, r.Weekdays = "week", workdays
+	} else if m = englishRecurrenceWeekend.FindStringSubmatch(rest); m != nil {
+		r.Unit, r.Weekdays = "week", weekends
+	} else if m = englishRecurrenceOrdinal.FindStringSubmatch(rest); m != nil {
+		r.Unit, r.Weekdays, r.Ordinal = "month", []time.Weekday{englishWeekdays[m[3]]}, atoi(m[1])
+		if len(m[2]) != 0 {
+			r.Ordinal = -1
+		} else if r.Ordinal < 1 || 5 < r.Ordinal {
+			return nil, value, fmt.Errorf("invalid week of the month %s", m[1])
+		}
+	} else if m = englishRecurrenceWeekday.FindStringSubmatch(rest); m != nil {
+		r.Unit, r.Weekdays = "week", []time.Weekday{englishWeekdays[m[2]]}
+		if len(m[1]) != 0 {
+			r.Interval = 2
+		}
+		for {
+			rest = rest[len(m[0]):]
+			if m = englishRecurrenceMoreDay.FindStringSubmatch(rest); m == nil {
+				return r, rest, nil
+			}
+			r.Weekdays = append(r.Weekdays, englishWeekdays[m[1]])
+		}
+	} else if m = englishRecurrenceDayOfYear1.FindStringSubmatch(rest); m != nil {
+		r.Unit, r.Month, r.Day = "year", englishMonths[m[1]], atoi(m[2])
+	} else if m = englishRecurrenceDayOfYear2.FindStringSubmatch(rest); m != nil {
+		r.Unit, r.Month, r.Day = "year", englishMonths[m[2]], atoi(m[1])
+	} else if m = englishRecurrenceDay.FindStringSubmatch(rest); m != nil {
+		r.Unit, r.Day = "month", atoi(m[1])
+	} else {
+		return nil, value, fmt.Errorf("unknown recurrence %q", rest)
+	}
+	if err := r.validateDay(); err != nil {
+		return nil, value, err
+	}
+	return r, rest[len(m[0]):], nil
+}
+
+func parseJapaneseRecurrence(value string) (*Recurrence, string, error) {
+	m := japaneseRecurrencePrefix.FindStringSubmatch(value)
+	if m == nil {
+		return nil, value, nil
+	}
+	rest := value[len(m[0]):]
+	r := &Recurrence{Interval: 1}
+	switch {
+	case strings.HasSuffix(m[0], "平日"):
+		r.Unit, r.Weekdays = "week", workdays
+		return r, rest, nil
+	case m[0] == "隔週":
+		r.Unit, r.Interval = "week", 2
+	case len(m[2]) != 0:
+		r.Unit = recurrenceUnits[m[3]]
+		if r.Interval = atoi(m[2]); r.Interval < 1 {
+			return nil, value, fmt.Errorf("invalid interval %s", m[2])
+		}
+		return r, rest, nil
+	default:
+		r.Unit = map[string]string{"日": "day", "週": "week", "月": "month", "年": "year"}[m[1]]
+	}
+	rest = strings.TrimLeft(rest, dueSeparators)
+	m = nil
+	switch r.Unit {
+	case "week":
+		if m = japaneseRecurrenceWeekday.FindStringSubmatch(rest); m == nil {
+			return r, rest, nil
+		}
+		r.Weekdays = []time.Weekday{japaneseWeekdays[m[1]]}
+		for {
+			rest = rest[len(m[0]):]
+			if m = japaneseRecurrenceMoreDay.FindStringSubmatch(rest); m == nil {
+				return r, rest, nil
+			}
+			r.Weekdays = append(r.Weekdays, japaneseWeekdays[m[1]])
+		}
+	case "month":
+		if m = japaneseRecurrenceOrdinal.FindStringSubmatch(rest); m != nil {
+			r.Weekdays, r.Ordinal = []time.Weekday{japaneseWeekdays[m[3]]}, atoi(m[1])
+			if len(m[2]) != 0 {
+				r.Ordinal = -1
+			} else if r.Ordinal < 1 || 5 < r.Ordinal {
+				return nil, value, fmt.Errorf("invalid week of the month %s", m[1])
+			}
+			return r, rest[len(m[0]):], nil
+		}
+		if m = japaneseRecurrenceDay.FindStringSubmatch(rest); m != nil {
+			r.Day = atoi(m[1])
+		}
+	case "year":
+		if m = japaneseRecurrenceDayOfYear.FindStringSubmatch(rest); m != nil {
+			r.Month, r.Day = time.Month(atoi(m[1])), atoi(m[2])
+		}
+	}
+	if m == nil {
+		return r, rest, nil
+	}
+	if err := r.validateDay(); err != nil {
+		return nil, value, err
+	}
+	return r, rest[len(m[0]):], nil
+}
+
+func (r Recurrence) validateDay() error {
+	if r.Month != 0 && (r.Month < time.January || time.December < r.Month) {
+		return fmt.Errorf("invalid month %d", r.Month)
+	}
+	if r.Month != 0 || r.Day != 0 {
+		if r.Day < 1 || 31 < r.Day {
+			return fmt.Errorf("invalid day %d", r.Day)
+		}
+	}
+	return nil
+}
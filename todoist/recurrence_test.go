@@ -0,0 +1,134 @@
+package todoist
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		value  string
+		lang   string
+		expect Recurrence
+	}{
+		{"every day", "", Recurrence{Unit: "day", Interval: 1}},
+		{"daily", "en", Recurrence{Unit: "day", Interval: 1}},
+		{"every 2 weeks", "en", Recurrence{Unit: "week", Interval: 2}},
+		{"every other month", "en", Recurrence{Unit: "month", Interval: 2}},
+		{"every! 3 days", "en", Recurrence{Unit: "day", Interval: 3, FromCompletion: true}},
+		{"every monday", "en", Recurrence{Unit: "week", Interval: 1, Weekdays: []time.Weekday{time.Monday}}},
+		{"every other tue", "en", Recurrence{Unit: "week", Interval: 2, Weekdays: []time.Weekday{time.Tuesday}}},
+		{"every mon, wed and fri at 9am", "en", Recurrence{Unit: "week", Interval: 1,
+			Weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday}, HasTime: true, Hour: 9}},
+		{"every workday 17:30", "en", Recurrence{Unit: "week", Interval: 1, Weekdays: workdays, HasTime: true, Hour: 17, Minute: 30}},
+		{"every 3rd friday", "en", Recurrence{Unit: "month", Interval: 1, Weekdays: []time.Weekday{time.Friday}, Ordinal: 3}},
+		{"every last sun", "en", Recurrence{Unit: "month", Interval: 1, Weekdays: []time.Weekday{time.Sunday}, Ordinal: -1}},
+		{"every 15th", "en", Recurrence{Unit: "month", Interval: 1, Day: 15}},
+		{"every jan 27", "en", Recurrence{Unit: "year", Interval: 1, Month: time.January, Day: 27}},
+		{"毎日 9時", "ja", Recurrence{Unit: "day", Interval: 1, HasTime: true, Hour: 9}},
+		{"毎週月曜・木曜", "ja", Recurrence{Unit: "week", Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Thursday}}},
+		{"隔週金曜", "ja", Recurrence{Unit: "week", Interval: 2, Weekdays: []time.Weekday{time.Friday}}},
+		{"平日", "ja", Recurrence{Unit: "week", Interval: 1, Weekdays: workdays}},
+		{"3日ごと", "ja", Recurrence{Unit: "day", Interval: 3}},
+		{"毎月第3金曜", "ja", Recurrence{Unit: "month", Interval: 1, Weekdays: []time.Weekday{time.Friday}, Ordinal: 3}},
+		{"毎月15日", "ja", Recurrence{Unit: "month", Interval: 1, Day: 15}},
+		{"毎年1月27日", "ja", Recurrence{Unit: "year", Interval: 1, Month: time.January, Day: 27}},
+	}
+	for _, test := range tests {
+		r, err := ParseRecurrence(test.value, test.lang)
+		if err != nil {
+			t.Errorf("%s: Unexpect error: %s", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(*r, test.expect) {
+			t.Errorf("%s: Expect %+v, but got %+v", test.value, test.expect, *r)
+		}
+	}
+	for _, value := range []string{"tomorrow", "every", "every 0 days", "every 6th friday", "every 32nd", "every day tomorrow"} {
+		if r, err := ParseRecurrence(value, "en"); err == nil {
+			t.Errorf("%s: Expect error, but got %+v", value, r)
+		}
+	}
+}
+
+func TestRecurrence_NextN(t *testing.T) {
+	loc := time.FixedZone("JST", 9*60*60)
+	date := func(year int, month time.Month, day, hour, minute int) Time {
+		return Time{time.Date(year, month, day, hour, minute, 0, 0, loc)}
+	}
+	// Wednesday
+	from := date(2020, 5, 13, 0, 0)
+	tests := []struct {
+		value  string
+		from   Time
+		expect []Time
+	}{
+		{"every day", from, []Time{date(2020, 5, 14, 0, 0), date(2020, 5, 15, 0, 0), date(2020, 5, 16, 0, 0)}},
+		{"every 2 weeks", from, []Time{date(2020, 5, 27, 0, 0), date(2020, 6, 10, 0, 0), date(2020, 6, 24, 0, 0)}},
+		{"every monday", from, []Time{date(2020, 5, 18, 0, 0), date(2020, 5, 25, 0, 0), date(2020, 6, 1, 0, 0)}},
+		{"every workday", date(2020, 5, 15, 0, 0), []Time{date(2020, 5, 18, 0, 0), date(2020, 5, 19, 0, 0), date(2020, 5, 20, 0, 0)}},
+		{"every 3rd friday", from, []Time{date(2020, 5, 15, 0, 0), date(2020, 6, 19, 0, 0), date(2020, 7, 17, 0, 0)}},
+		{"every last fri", from, []Time{date(2020, 5, 29, 0, 0), date(2020, 6, 26, 0, 0), date(2020, 7, 31, 0, 0)}},
+		{"every other mon, fri", from, []Time{date(2020, 5, 15, 0, 0), date(2020, 5, 25, 0, 0), date(2020, 5, 29, 0, 0)}},
+		{"every month", date(2020, 1, 31, 0, 0), []Time{date(2020, 2, 29, 0, 0), date(2020, 3, 29, 0, 0), date(2020, 4, 29, 0, 0)}},
+		{"every 31st", date(2020, 1, 31, 0, 0), []Time{date(2020, 2, 29, 0, 0), date(2020, 3, 31, 0, 0), date(2020, 4, 30, 0, 0)}},
+		{"every feb 29", from, []Time{date(2021, 2, 28, 0, 0), date(2022, 2, 28, 0, 0), date(2023, 2, 28, 0, 0)}},
+		{"every day at 9am", date(2020, 5, 13, 8, 0), []Time{date(2020, 5, 13, 9, 0), date(2020, 5, 14, 9, 0)}},
+		{"every week", date(2020, 5, 13, 18, 30), []Time{date(2020, 5, 20, 18, 30), date(2020, 5, 27, 18, 30)}},
+	}
+	for _, test := range tests {
+		r, err := ParseRecurrence(test.value, "en")
+		if err != nil {
+			t.Errorf("%s: Unexpect error: %s", test.value, err)
+			continue
+		}
+		actual := r.NextN(test.from, len(test.expect), loc)
+		if len(actual) != len(test.expect) {
+			t.Errorf("%s: Expect %v, but got %v", test.value, test.expect, actual)
+			continue
+		}
+		for i := range actual {
+			if !actual[i].Equal(test.expect[i]) {
+				t.Errorf("%s: Expect %v, but got %v", test.value, test.expect, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestDue_Next(t *testing.T) {
+	loc := time.FixedZone("JST", 9*60*60)
+	date := func(month time.Month, day, hour, minute int) Time {
+		return Time{time.Date(2020, month, day, hour, minute, 0, 0, loc)}
+	}
+	tests := []struct {
+		due       Due
+		completed Time
+		expect    Time
+	}{
+		// completed on the due date
+		{Due{Date: date(5, 13, 0, 0), String: "every day", IsRecurring: true}, date(5, 13, 10, 0), date(5, 14, 0, 0)},
+		// completed before the due date
+		{Due{Date: date(5, 18, 0, 0), String: "every monday", IsRecurring: true}, date(5, 13, 10, 0), date(5, 25, 0, 0)},
+		// passed occurrences are skipped
+		{Due{Date: date(5, 1, 0, 0), String: "every 3 days", IsRecurring: true}, date(5, 13, 10, 0), date(5, 13, 0, 0)},
+		{Due{Date: Time{date(5, 10, 9, 0).UTC()}, String: "every day 9am", IsRecurring: true}, date(5, 13, 10, 0), Time{date(5, 14, 9, 0).UTC()}},
+		{Due{Date: date(5, 1, 18, 0), String: "毎週", Lang: "ja", IsRecurring: true}, date(5, 13, 10, 0), date(5, 15, 18, 0)},
+		// counted from the completion
+		{Due{Date: date(5, 1, 0, 0), String: "every! 3 days", IsRecurring: true}, date(5, 13, 10, 0), date(5, 16, 0, 0)},
+	}
+	for _, test := range tests {
+		due, err := test.due.Next(test.completed, loc)
+		if err != nil {
+			t.Errorf("%s: Unexpect error: %s", test.due.String, err)
+			continue
+		}
+		if !due.Date.Equal(test.expect) || due.Date.Location() != test.expect.Location() {
+			t.Errorf("%s: Expect %v, but got %v", test.due.String, test.expect.Time, due.Date.Time)
+		}
+	}
+	if _, err := (Due{Date: date(5, 13, 0, 0), String: "tomorrow"}).Next(date(5, 13, 0, 0), loc); err == nil {
+		t.Error("Expect error for the due date which is not recurring, but got nil")
+	}
+}
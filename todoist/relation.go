@@ -1,17 +1,24 @@
 package todoist
 
+import (
+	"time"
+)
+
 type RelationClient struct {
 	*Client
 }
 
 type ItemRelations struct {
-	//Users map[ID]User
+	Users    map[ID]Collaborator
 	Projects map[ID]Project
+	Sections map[ID]Section
 	Labels   map[ID]Label
+	// Location is the timezone of the account to show dates in.
+	Location *time.Location
 }
 
 func (c RelationClient) Items(items []Item) ItemRelations {
-	res := ItemRelations{Projects: map[ID]Project{}, Labels: map[ID]Label{}}
+	res := ItemRelations{Users: map[ID]Collaborator{}, Projects: map[ID]Project{}, Sections: map[ID]Section{}, Labels: map[ID]Label{}, Location: c.Location()}
 	for _, item := range items {
 		if _, ok := res.Projects[item.ProjectID]; !ok {
 			p := c.Project.Resolve(item.ProjectID)
@@ -19,6 +26,20 @@ func (c RelationClient) Items(items []Item) ItemRelations {
 				res.Projects[item.ProjectID] = *p
 			}
 		}
+		if _, ok := res.Sections[item.SectionID]; !ok && !item.SectionID.IsZero() {
+			s := c.Section.Resolve(item.SectionID)
+			if s != nil {
+				res.Sections[item.SectionID] = *s
+			}
+		}
+		for _, uid := range []ID{item.ResponsibleUID, item.AssignedByUID} {
+			if _, ok := res.Users[uid]; !ok && !uid.IsZero() {
+				u := c.Collaborator.ResolveUser(uid)
+				if u != nil {
+					res.Users[uid] = *u
+				}
+			}
+		}
 		for _, id := range item.Labels {
 			if _, ok := res.Labels[id]; !ok {
 				l := c.Label.Resolve(id)
@@ -30,3 +51,36 @@ func (c RelationClient) Items(items []Item) ItemRelations {
 	}
 	return res
 }
+
+type NotificationRelations struct {
+	Users    map[ID]Collaborator
+	Projects map[ID]Project
+	Items    map[ID]Item
+	// Location is the timezone of the account to show dates in.
+	Location *time.Location
+}
+
+func (c RelationClient) LiveNotifications(notifications []LiveNotification) NotificationRelations {
+	res := NotificationRelations{Users: map[ID]Collaborator{}, Projects: map[ID]Project{}, Items: map[ID]Item{}, Location: c.Location()}
+	for _, n := range notifications {
+		if _, ok := res.Users[n.FromUID]; !ok && !n.FromUID.IsZero() {
+			u := c.Collaborator.ResolveUser(n.FromUID)
+			if u != nil {
+				res.Users[n.FromUID] = *u
+			}
+		}
+		if _, ok := res.Projects[n.ProjectID]; !ok && !n.ProjectID.IsZero() {
+			p := c.Project.Resolve(n.ProjectID)
+			if p != nil {
+				res.Projects[n.ProjectID] = *p
+			}
+		}
+		if _, ok := res.Items[n.ItemID]; !ok && !n.ItemID.IsZero() {
+			i := c.Item.Resolve(n.ItemID)
+			if i != nil {
+				res.Items[n.ItemID] = *i
+			}
+		}
+	}
+	return res
+}
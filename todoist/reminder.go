@@ -1,16 +1,280 @@
 package todoist
 
+import (
+	"errors"
+	"fmt"
+)
+
 type Reminder struct {
 	Entity
-	NotifyUID  ID     `json:"notify_uid"`
+	NotifyUID  ID     `json:"notify_uid,omitempty"`
 	ItemID     ID     `json:"item_id"`
-	Service    string `json:"service"`
+	Service    string `json:"service,omitempty"`
 	Type       string `json:"type"`
-	Due        Due    `json:"due"`
+	Due        *Due   `json:"due,omitempty"`
 	MmOffset   int    `json:"mm_offset"`
-	Name       string `json:"name"`
-	LocLat     string `json:"loc_lat"`
-	LocLong    string `json:"loc_long"`
-	LocTrigger string `json:"loc_trigger"`
-	Radius     int    `json:"radius"`
+	Name       string `json:"name,omitempty"`
+	LocLat     string `json:"loc_lat,omitempty"`
+	LocLong    string `json:"loc_long,omitempty"`
+	LocTrigger string `json:"loc_trigger,omitempty"`
+	Radius     int    `json:"radius,omitempty"`
+}
+
+const (
+	ReminderTypeRelative = "relative"
+	ReminderTypeAbsolute = "absolute"
+	ReminderTypeLocation = "location"
+)
+
+func (r Reminder) String() string {
+	switch r.Type {
+	case ReminderTypeRelative:
+		return fmt.Sprintf("%d minutes before", r.MmOffset)
+	case ReminderTypeAbsolute:
+		if r.Due == nil {
+			return ""
+		}
+		return r.Due.Date.String()
+	case ReminderTypeLocation:
+		return fmt.Sprintf("%s %s (%s,%s)", r.LocTrigger, r.Name, r.LocLat, r.LocLong)
+	}
+	return r.Type
+}
+
+type NewReminderOpts struct {
+	NotifyUID ID
+	Service   string
+}
+
+func newReminder(itemID ID, typ string, opts *NewReminderOpts) (*Reminder, error) {
+	if itemID.IsZero() {
+		return nil, errors.New("new reminder requires an item id")
+	}
+	reminder := Reminder{
+		ItemID:    itemID,
+		Type:      typ,
+		NotifyUID: opts.NotifyUID,
+		Service:   opts.Service,
+	}
+	reminder.ID = GenerateTempID()
+	return &reminder, nil
+}
+
+// NewRelativeReminder returns a reminder notified the minutes before the due date of the item.
+func NewRelativeReminder(itemID ID, minutes int, opts *NewReminderOpts) (*Reminder, error) {
+	if minutes < 0 {
+		return nil, errors.New("relative reminder requires positive minutes")
+	}
+	reminder, err := newReminder(itemID, ReminderTypeRelative, opts)
+	if err != nil {
+		return nil, err
+	}
+	reminder.MmOffset = minutes
+	return reminder, nil
+}
+
+// NewAbsoluteReminder returns a reminder notified at the date.
+func NewAbsoluteReminder(itemID ID, due Due, opts *NewReminderOpts) (*Reminder, error) {
+	if due.Date.IsZero() {
+		return nil, errors.New("absolute reminder requires a due date")
+	}
+	reminder, err := newReminder(itemID, ReminderTypeAbsolute, opts)
+	if err != nil {
+		return nil, err
+	}
+	reminder.Due = &due
+	return reminder, nil
+}
+
+// NewLocationReminder returns a reminder notified when entering or leaving the location.
+// The trigger is either "on_enter" or "on_leave".
+func NewLocationReminder(itemID ID, name, lat, long, trigger string, radius int, opts *NewReminderOpts) (*Reminder, error) {
+	if len(name) == 0 || len(lat) == 0 || len(long) == 0 {
+		return nil, errors.New("location reminder requires a name, a latitude and a longitude")
+	}
+	if trigger != "on_enter" && trigger != "on_leave" {
+		return nil, fmt.Errorf("invalid location trigger: %s", trigger)
+	}
+	reminder, err := newReminder(itemID, ReminderTypeLocation, opts)
+	if err != nil {
+		return nil, err
+	}
+	reminder.Name = name
+	reminder.LocLat = lat
+	reminder.LocLong = long
+	reminder.LocTrigger = trigger
+	reminder.Radius = radius
+	return reminder, nil
+}
+
+type ReminderClient struct {
+	*Client
+	cache *reminderCache
+}
+
+func (c ReminderClient) Add(reminder Reminder) (*Reminder, error) {
+	command := Command{
+		Type:   "reminder_add",
+		Args:   reminder,
+		UUID:   GenerateUUID(),
+		TempID: reminder.ID,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.store(reminder)
+	c.queue = append(c.queue, command)
+	return &reminder, nil
+}
+
+func (c ReminderClient) Update(reminder Reminder) (*Reminder, error) {
+	command := Command{
+		Type: "reminder_update",
+		Args: reminder,
+		UUID: GenerateUUID(),
+	}
+	c.enqueue(command)
+	return &reminder, nil
+}
+
+func (c ReminderClient) Delete(id ID) error {
+	command := Command{
+		Type: "reminder_delete",
+		UUID: GenerateUUID(),
+		Args: map[string]ID{
+			"id": id,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+// ClearLocations clears the locations used by location reminders.
+func (c ReminderClient) ClearLocations() error {
+	command := Command{
+		Type: "clear_locations",
+		UUID: GenerateUUID(),
+		Args: map[string]interface{}{},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c ReminderClient) GetAll() []Reminder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.getAll()
+}
+
+func (c ReminderClient) Resolve(id ID) *Reminder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(c.tempIDs.Resolve(id))
+}
+
+// GetAllForItem returns all the cached reminders of the given item.
+func (c ReminderClient) GetAllForItem(itemID ID) []Reminder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.byItem, []ID{c.tempIDs.Resolve(itemID)})
+}
+
+type reminderCache struct {
+	reminders map[ID]Reminder
+	order     *orderedIDs
+	byItem    idIndex
+}
+
+func newReminderCache() *reminderCache {
+	c := &reminderCache{}
+	c.reset()
+	return c
+}
+
+func (c *reminderCache) reset() {
+	c.reminders = map[ID]Reminder{}
+	c.order = newOrderedIDs()
+	c.byItem = idIndex{}
+}
+
+func (c *reminderCache) getAll() []Reminder {
+	res := make([]Reminder, 0, len(c.reminders))
+	for _, id := range c.order.list() {
+		res = append(res, c.reminders[id])
+	}
+	return res
+}
+
+func (c *reminderCache) resolve(id ID) *Reminder {
+	if r, ok := c.reminders[id]; ok {
+		return &r
+	}
+	return nil
+}
+
+// find returns the reminders indexed by any of the keys in insertion order.
+func (c *reminderCache) find(index idIndex, keys []ID) []Reminder {
+	seen := map[ID]struct{}{}
+	var ids []ID
+	for _, key := range keys {
+		for _, id := range index.get(key) {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	c.order.sort(ids)
+	var res []Reminder
+	for _, id := range ids {
+		res = append(res, c.reminders[id])
+	}
+	return res
+}
+
+func (c *reminderCache) store(r Reminder) {
+	if r.IsDeleted.Bool() {
+		c.remove(r)
+		return
+	}
+	if old, ok := c.reminders[r.ID]; ok {
+		c.unindex(old)
+	}
+	c.reminders[r.ID] = r
+	c.order.add(r.ID)
+	c.index(r)
+}
+
+func (c *reminderCache) remove(r Reminder) {
+	old, ok := c.reminders[r.ID]
+	if !ok {
+		return
+	}
+	c.unindex(old)
+	delete(c.reminders, r.ID)
+	c.order.remove(r.ID)
+}
+
+func (c *reminderCache) index(r Reminder) {
+	c.byItem.add(r.ItemID, r.ID)
+}
+
+func (c *reminderCache) unindex(r Reminder) {
+	c.byItem.remove(r.ItemID, r.ID)
+}
+
+func (c *reminderCache) replaceTempIDs(mapping TempIDMapping) {
+	for _, id := range c.order.tempIDTargets(mapping, c.byItem) {
+		old := c.reminders[id]
+		r := old
+		r.ID = mapping.Resolve(r.ID)
+		r.ItemID = mapping.Resolve(r.ItemID)
+		c.unindex(old)
+		delete(c.reminders, old.ID)
+		c.order.rename(old.ID, r.ID)
+		if existing, ok := c.reminders[r.ID]; ok {
+			c.unindex(existing)
+		}
+		c.reminders[r.ID] = r
+		c.index(r)
+	}
 }
@@ -0,0 +1,100 @@
+package todoist
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewReminder(t *testing.T) {
+	opts := &NewReminderOpts{}
+	if _, err := NewRelativeReminder("", 30, opts); err == nil {
+		t.Error("Expect error, but got nil")
+	}
+	r, err := NewRelativeReminder("1", 30, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(r)
+	var args map[string]interface{}
+	json.Unmarshal(b, &args)
+	if args["type"] != ReminderTypeRelative || args["mm_offset"] != float64(30) {
+		t.Errorf("Unexpect args: %s", b)
+	}
+	if _, ok := args["due"]; ok {
+		t.Errorf("Expect no due, but got %s", b)
+	}
+
+	due := Due{Date: Time{time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)}}
+	if r, err = NewAbsoluteReminder("1", due, opts); err != nil || r.Due == nil || r.Type != ReminderTypeAbsolute {
+		t.Errorf("Unexpect reminder: %v, %v", r, err)
+	}
+	if _, err = NewAbsoluteReminder("1", Due{}, opts); err == nil {
+		t.Error("Expect error, but got nil")
+	}
+
+	if r, err = NewLocationReminder("1", "office", "35.68", "139.76", "on_enter", 100, opts); err != nil || r.Type != ReminderTypeLocation {
+		t.Errorf("Unexpect reminder: %v, %v", r, err)
+	}
+	if _, err = NewLocationReminder("1", "office", "35.68", "139.76", "on_stay", 100, opts); err == nil {
+		t.Error("Expect error, but got nil")
+	}
+}
+
+func TestReminderClient(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	reminder := func(id, itemID string) Reminder {
+		r := Reminder{ItemID: ID(itemID), Type: ReminderTypeRelative}
+		r.ID = ID(id)
+		return r
+	}
+	c.updateState(&SyncState{
+		FullSync:  true,
+		Reminders: []Reminder{reminder("1", "10"), reminder("2", "20"), reminder("3", "10")},
+	})
+	deleted := reminder("1", "10")
+	deleted.IsDeleted = true
+	c.updateState(&SyncState{Reminders: []Reminder{deleted}})
+	if rs := c.Reminder.GetAllForItem("10"); len(rs) != 1 || rs[0].ID != "3" {
+		t.Errorf("Unexpect reminders: %v", rs)
+	}
+
+	r, err := NewRelativeReminder("20", 10, &NewReminderOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Reminder.Add(*r)
+	if rs := c.Reminder.GetAllForItem("20"); len(rs) != 2 {
+		t.Errorf("Expect %d, but got %d", 2, len(rs))
+	}
+	c.replaceTempIDs(TempIDMapping{r.ID: "4"})
+	if c.Reminder.Resolve(r.ID) == nil || c.Reminder.Resolve("4") == nil {
+		t.Error("Expect the reminder resolved by both ids, but got nil")
+	}
+	if len(c.snapshot().Reminders) != 3 {
+		t.Errorf("Expect %d, but got %d", 3, len(c.snapshot().Reminders))
+	}
+}
+
+func TestReminderClient_AddAtDueTime(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	r, err := NewRelativeReminder("20", 0, &NewReminderOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Reminder.Add(*r)
+	b, err := json.Marshal(c.Queue()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var command struct {
+		Args map[string]interface{} `json:"args"`
+	}
+	json.Unmarshal(b, &command)
+	if offset, ok := command.Args["mm_offset"]; !ok || offset != float64(0) {
+		t.Errorf("Expect mm_offset 0, but got %s", b)
+	}
+}
@@ -0,0 +1,101 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures retries of requests which failed by transient errors,
+// such as network errors, 5xx responses and rate limiting.
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used by clients created with NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 4,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+}
+
+// backoff returns exponential backoff with jitter for the attempt (0-origin).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff
+	for i := 0; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// StatusError is returned when the server responds with an unexpected status code.
+type StatusError struct {
+	Op         string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to %s, status code: %d", e.Op, e.StatusCode)
+}
+
+// IsTemporary reports whether the request failed by a transient error which may succeed later,
+// i.e. network errors, rate limiting and server errors.
+// Other errors, such as an invalid token or a rejected request, fail again.
+func IsTemporary(err error) bool {
+	switch e := err.(type) {
+	case *StatusError:
+		return isTemporaryStatus(e.StatusCode)
+	case net.Error:
+		return true
+	}
+	return false
+}
+
+func isTemporaryStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code/100 == 5
+}
+
+func shouldRetry(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return isTemporaryStatus(res.StatusCode)
+}
+
+// retryAfter parses Retry-After header, which is either seconds or http date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	v := res.Header.Get("Retry-After")
+	if len(v) == 0 {
+		return 0, false
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		if sec < 0 {
+			sec = 0
+		}
+		return time.Duration(sec) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,288 @@
+package todoist
+
+import (
+	"errors"
+	"strings"
+)
+
+type Section struct {
+	Entity
+	Name         string  `json:"name"`
+	ProjectID    ID      `json:"project_id"`
+	SectionOrder int     `json:"section_order"`
+	Collapsed    IntBool `json:"collapsed"`
+	UserID       ID      `json:"user_id,omitempty"`
+	SyncID       ID      `json:"sync_id,omitempty"`
+	IsArchived   IntBool `json:"is_archived"`
+	DateArchived Time    `json:"date_archived"`
+	DateAdded    Time    `json:"date_added"`
+}
+
+func (s Section) String() string {
+	return "/" + s.Name
+}
+
+func (s Section) ColorString() string {
+	return s.String()
+}
+
+type NewSectionOpts struct {
+	SectionOrder int
+}
+
+func NewSection(name string, projectID ID, opts *NewSectionOpts) (*Section, error) {
+	if len(name) == 0 || projectID.IsZero() {
+		return nil, errors.New("new section requires a name and a project id")
+	}
+	section := Section{
+		Name:         name,
+		ProjectID:    projectID,
+		SectionOrder: opts.SectionOrder,
+	}
+	section.ID = GenerateTempID()
+	return &section, nil
+}
+
+type SectionClient struct {
+	*Client
+	cache *sectionCache
+}
+
+func (c *SectionClient) Add(section Section) (*Section, error) {
+	command := Command{
+		Type:   "section_add",
+		Args:   section,
+		UUID:   GenerateUUID(),
+		TempID: section.ID,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.store(section)
+	c.queue = append(c.queue, command)
+	return &section, nil
+}
+
+func (c *SectionClient) Update(section Section) (*Section, error) {
+	command := Command{
+		Type: "section_update",
+		Args: section,
+		UUID: GenerateUUID(),
+	}
+	c.enqueue(command)
+	return &section, nil
+}
+
+func (c *SectionClient) Move(id, projectID ID) error {
+	command := Command{
+		Type: "section_move",
+		UUID: GenerateUUID(),
+		Args: map[string]ID{
+			"id":         id,
+			"project_id": projectID,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+// Reorder updates the order of the sections in a project by their SectionOrder.
+func (c *SectionClient) Reorder(sections []Section) error {
+	var args []map[string]interface{}
+	for _, section := range sections {
+		args = append(args, map[string]interface{}{
+			"id":            section.ID,
+			"section_order": section.SectionOrder,
+		})
+	}
+	command := Command{
+		Type: "section_reorder",
+		UUID: GenerateUUID(),
+		Args: map[string]interface{}{
+			"sections": args,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c *SectionClient) Archive(id ID) error {
+	command := Command{
+		Type: "section_archive",
+		UUID: GenerateUUID(),
+		Args: map[string]ID{
+			"id": id,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c *SectionClient) Unarchive(id ID) error {
+	command := Command{
+		Type: "section_unarchive",
+		UUID: GenerateUUID(),
+		Args: map[string]ID{
+			"id": id,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c *SectionClient) Delete(id ID) error {
+	command := Command{
+		Type: "section_delete",
+		UUID: GenerateUUID(),
+		Args: map[string]ID{
+			"id": id,
+		},
+	}
+	c.enqueue(command)
+	return nil
+}
+
+func (c *SectionClient) GetAll() []Section {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.getAll()
+}
+
+func (c *SectionClient) Resolve(id ID) *Section {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.resolve(c.tempIDs.Resolve(id))
+}
+
+// FindByProjectID returns the sections of the project.
+func (c SectionClient) FindByProjectID(id ID) []Section {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.find(c.cache.byProject, []ID{id})
+}
+
+func (c SectionClient) FindByName(substr string) []Section {
+	if r := []rune(substr); len(r) > 0 && string(r[0]) == "/" {
+		substr = string(r[1:])
+	}
+	var res []Section
+	for _, s := range c.GetAll() {
+		if strings.Contains(s.Name, substr) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func (c SectionClient) FindOneByName(substr string) *Section {
+	sections := c.FindByName(substr)
+	for _, section := range sections {
+		if section.Name == substr {
+			return &section
+		}
+	}
+	if len(sections) > 0 {
+		return &sections[0]
+	}
+	return nil
+}
+
+type sectionCache struct {
+	sections  map[ID]Section
+	order     *orderedIDs
+	byProject idIndex
+}
+
+func newSectionCache() *sectionCache {
+	c := &sectionCache{}
+	c.reset()
+	return c
+}
+
+func (c *sectionCache) reset() {
+	c.sections = map[ID]Section{}
+	c.order = newOrderedIDs()
+	c.byProject = idIndex{}
+}
+
+func (c *sectionCache) getAll() []Section {
+	res := make([]Section, 0, len(c.sections))
+	for _, id := range c.order.list() {
+		res = append(res, c.sections[id])
+	}
+	return res
+}
+
+func (c *sectionCache) resolve(id ID) *Section {
+	if s, ok := c.sections[id]; ok {
+		return &s
+	}
+	return nil
+}
+
+// find returns the sections indexed by any of the keys in insertion order.
+func (c *sectionCache) find(index idIndex, keys []ID) []Section {
+	seen := map[ID]struct{}{}
+	var ids []ID
+	for _, key := range keys {
+		for _, id := range index.get(key) {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	c.order.sort(ids)
+	var res []Section
+	for _, id := range ids {
+		res = append(res, c.sections[id])
+	}
+	return res
+}
+
+func (c *sectionCache) store(s Section) {
+	if s.IsDeleted.Bool() {
+		c.remove(s)
+		return
+	}
+	if old, ok := c.sections[s.ID]; ok {
+		c.unindex(old)
+	}
+	c.sections[s.ID] = s
+	c.order.add(s.ID)
+	c.index(s)
+}
+
+func (c *sectionCache) remove(s Section) {
+	old, ok := c.sections[s.ID]
+	if !ok {
+		return
+	}
+	c.unindex(old)
+	delete(c.sections, s.ID)
+	c.order.remove(s.ID)
+}
+
+func (c *sectionCache) index(s Section) {
+	c.byProject.add(s.ProjectID, s.ID)
+}
+
+func (c *sectionCache) unindex(s Section) {
+	c.byProject.remove(s.ProjectID, s.ID)
+}
+
+func (c *sectionCache) replaceTempIDs(mapping TempIDMapping) {
+	for _, id := range c.order.tempIDTargets(mapping, c.byProject) {
+		old := c.sections[id]
+		s := old
+		s.ID = mapping.Resolve(s.ID)
+		s.ProjectID = mapping.Resolve(s.ProjectID)
+		c.unindex(old)
+		delete(c.sections, old.ID)
+		c.order.rename(old.ID, s.ID)
+		if existing, ok := c.sections[s.ID]; ok {
+			c.unindex(existing)
+		}
+		c.sections[s.ID] = s
+		c.index(s)
+	}
+}
@@ -0,0 +1,48 @@
+package todoist
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSectionClient(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	var state SyncState
+	if err := json.Unmarshal([]byte(`{
+		"sections": [
+			{"id": 1, "name": "foo", "project_id": 10, "section_order": 1, "collapsed": false, "is_archived": false, "is_deleted": false},
+			{"id": 2, "name": "bar", "project_id": 20, "section_order": 1, "collapsed": true, "is_archived": false, "is_deleted": false},
+			{"id": 3, "name": "baz", "project_id": 10, "section_order": 2, "collapsed": false, "is_archived": false, "is_deleted": false}
+		]
+	}`), &state); err != nil {
+		t.Fatal(err)
+	}
+	state.FullSync = true
+	c.updateState(&state)
+	if s := c.Section.FindByProjectID("10"); len(s) != 2 || s[0].Name != "foo" || s[1].Name != "baz" {
+		t.Errorf("Unexpect sections: %v", s)
+	}
+	if s := c.Section.Resolve("2"); s == nil || !s.Collapsed.Bool() {
+		t.Errorf("Unexpect section: %v", s)
+	}
+
+	section, err := NewSection("qux", "20", &NewSectionOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Section.Add(*section)
+	item, _ := NewItem("item", &NewItemOpts{ProjectID: "20", SectionID: section.ID})
+	c.Item.Add(*item)
+	c.replaceTempIDs(TempIDMapping{section.ID: "4"})
+	if s := c.Section.FindByProjectID("20"); len(s) != 2 || s[1].ID != "4" {
+		t.Errorf("Unexpect sections: %v", s)
+	}
+	if items := c.Item.FindBySectionID("4"); len(items) != 1 || items[0].Content != "item" {
+		t.Errorf("Unexpect items: %v", items)
+	}
+	if len(c.snapshot().Sections) != 4 {
+		t.Errorf("Expect %d, but got %d", 4, len(c.snapshot().Sections))
+	}
+}
@@ -0,0 +1,353 @@
+package todoist
+
+import (
+	"encoding/json"
+	"errors"
+	"go.etcd.io/bbolt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Storage when the key does not exist.
+var ErrNotFound = errors.New("not found in the storage")
+
+// Storage persists the cached state, the sync token and the command queue of a client.
+type Storage interface {
+	Load(key string) ([]byte, error)
+	Save(key string, value []byte) error
+	Delete(key string) error
+}
+
+// Locker is implemented by storages shared between processes.
+// Lock blocks until the storage is locked exclusively, and returns the function to unlock it.
+type Locker interface {
+	Lock() (func() error, error)
+}
+
+// Lister is implemented by storages which can list their keys.
+// EncryptedStorage needs it to encrypt the values saved before the encryption is enabled.
+type Lister interface {
+	Keys() ([]string, error)
+}
+
+// lockStorage locks the storage if it is a Locker.
+func lockStorage(storage Storage) (func() error, error) {
+	if l, ok := storage.(Locker); ok {
+		return l.Lock()
+	}
+	return func() error { return nil }, nil
+}
+
+// writeFileAtomic writes the data to a temporary file in the same directory, then renames it to the file.
+// Readers never see a partially written file.
+func writeFileAtomic(file string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	// TempFile creates the file with 0600, but make sure of it.
+	if err = os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// FileStorage stores each key as a file in the directory.
+type FileStorage struct {
+	Dir string
+}
+
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if _, err := os.Stat(dir); err != nil {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+	return &FileStorage{Dir: dir}, nil
+}
+
+func (s *FileStorage) Load(key string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path.Join(s.Dir, key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return b, err
+}
+
+func (s *FileStorage) Save(key string, value []byte) error {
+	return writeFileAtomic(path.Join(s.Dir, key), value)
+}
+
+func (s *FileStorage) Delete(key string) error {
+	if err := os.Remove(path.Join(s.Dir, key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Keys returns the names of the files in the directory except for hidden ones such as the lock file.
+func (s *FileStorage) Keys() ([]string, error) {
+	files, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, f := range files {
+		if !f.IsDir() && !strings.HasPrefix(f.Name(), ".") {
+			keys = append(keys, f.Name())
+		}
+	}
+	return keys, nil
+}
+
+// Lock locks the directory with the lock file in it.
+func (s *FileStorage) Lock() (func() error, error) {
+	return lockFile(path.Join(s.Dir, ".lock"))
+}
+
+// MemoryStorage keeps values in memory. It is useful for tests and servers.
+type MemoryStorage struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{values: map[string][]byte{}}
+}
+
+func (s *MemoryStorage) Load(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, v...), nil
+}
+
+func (s *MemoryStorage) Save(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = append([]byte{}, value...)
+	return nil
+}
+
+func (s *MemoryStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+func (s *MemoryStorage) Keys() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.values))
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// KVFileStorage stores all the keys in a single bolt database file.
+// The database is opened for each operation, so that processes can share it.
+type KVFileStorage struct {
+	Path string
+}
+
+// kvBucket is the bucket of the database which holds the keys.
+var kvBucket = []byte("cache")
+
+// kvOpenTimeout is how long to wait for another process which opens the database.
+const kvOpenTimeout = 10 * time.Second
+
+func NewKVFileStorage(file string) (*KVFileStorage, error) {
+	dir := filepath.Dir(file)
+	if _, err := os.Stat(dir); err != nil {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+	s := &KVFileStorage{Path: file}
+	if err := s.migrateJSON(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrateJSON converts the file written by the former implementation, which kept the keys as a json object.
+func (s *KVFileStorage) migrateJSON() error {
+	unlock, err := s.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	b, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(b) == 0 || b[0] != '{' {
+		return nil
+	}
+	values := map[string][]byte{}
+	if err = json.Unmarshal(b, &values); err != nil {
+		return err
+	}
+	legacy := s.Path + ".json"
+	if err = os.Rename(s.Path, legacy); err != nil {
+		return err
+	}
+	err = s.update(func(bucket *bbolt.Bucket) error {
+		for k, v := range values {
+			if err := bucket.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return os.Remove(legacy)
+}
+
+func (s *KVFileStorage) open(readOnly bool) (*bbolt.DB, error) {
+	if readOnly {
+		if _, err := os.Stat(s.Path); os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+	}
+	return bbolt.Open(s.Path, 0600, &bbolt.Options{Timeout: kvOpenTimeout, ReadOnly: readOnly})
+}
+
+func (s *KVFileStorage) update(fn func(bucket *bbolt.Bucket) error) error {
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(kvBucket)
+		if err != nil {
+			return err
+		}
+		return fn(bucket)
+	})
+}
+
+func (s *KVFileStorage) Load(key string) ([]byte, error) {
+	db, err := s.open(true)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	var value []byte
+	err = db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(kvBucket)
+		if bucket == nil {
+			return ErrNotFound
+		}
+		v := bucket.Get([]byte(key))
+		if v == nil {
+			return ErrNotFound
+		}
+		// the value is valid only in the transaction
+		value = append([]byte{}, v...)
+		return nil
+	})
+	return value, err
+}
+
+func (s *KVFileStorage) Save(key string, value []byte) error {
+	return s.update(func(bucket *bbolt.Bucket) error {
+		return bucket.Put([]byte(key), value)
+	})
+}
+
+func (s *KVFileStorage) Delete(key string) error {
+	if _, err := os.Stat(s.Path); os.IsNotExist(err) {
+		return nil
+	}
+	return s.update(func(bucket *bbolt.Bucket) error {
+		return bucket.Delete([]byte(key))
+	})
+}
+
+func (s *KVFileStorage) Keys() ([]string, error) {
+	db, err := s.open(true)
+	if err == ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	var keys []string
+	err = db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(kvBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, _ []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	return keys, err
+}
+
+// Lock locks the file with the lock file next to it.
+func (s *KVFileStorage) Lock() (func() error, error) {
+	return lockFile(s.Path + ".lock")
+}
+
+// MigrateLegacyCache moves cache files named after the raw api token in the directory into the storage.
+// They were readable by anyone who can list the directory.
+func MigrateLegacyCache(dir, token string, storage Storage) error {
+	if len(token) == 0 {
+		return nil
+	}
+	for _, ext := range []string{".json", ".sync", ".queue"} {
+		legacy := path.Join(dir, token+ext)
+		b, err := ioutil.ReadFile(legacy)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		key := storageKeyPrefix(token) + ext
+		if _, err = storage.Load(key); err == ErrNotFound {
+			if err = storage.Save(key, b); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+		if err = os.Remove(legacy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
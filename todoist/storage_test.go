@@ -0,0 +1,215 @@
+package todoist
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fs, err := NewFileStorage(path.Join(dir, "files"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv, err := NewKVFileStorage(path.Join(dir, "kv", "cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []Storage{fs, NewMemoryStorage(), kv} {
+		if _, err := s.Load("foo"); err != ErrNotFound {
+			t.Errorf("%T: Expect %s, but got %v", s, ErrNotFound, err)
+		}
+		if err := s.Save("foo", []byte("bar")); err != nil {
+			t.Errorf("%T: Unexpect error: %s", s, err)
+		}
+		if err := s.Save("baz", []byte("qux")); err != nil {
+			t.Errorf("%T: Unexpect error: %s", s, err)
+		}
+		if b, err := s.Load("foo"); err != nil || string(b) != "bar" {
+			t.Errorf("%T: Expect %s, but got %s (%v)", s, "bar", string(b), err)
+		}
+		if err := s.Delete("foo"); err != nil {
+			t.Errorf("%T: Unexpect error: %s", s, err)
+		}
+		if err := s.Delete("foo"); err != nil {
+			t.Errorf("%T: Unexpect error: %s", s, err)
+		}
+		if _, err := s.Load("foo"); err != ErrNotFound {
+			t.Errorf("%T: Expect %s, but got %v", s, ErrNotFound, err)
+		}
+		if b, err := s.Load("baz"); err != nil || string(b) != "qux" {
+			t.Errorf("%T: Expect %s, but got %s (%v)", s, "qux", string(b), err)
+		}
+	}
+}
+
+func TestKVFileStorage_migrateJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "cache.db")
+	// "YmFy" is "bar" in base64, as the former implementation encoded the values
+	if err = ioutil.WriteFile(file, []byte(`{"foo":"YmFy"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewKVFileStorage(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := s.Load("foo"); err != nil || string(b) != "bar" {
+		t.Errorf("Expect %s, but got %s (%v)", "bar", string(b), err)
+	}
+	if _, err = os.Stat(file + ".json"); !os.IsNotExist(err) {
+		t.Errorf("Expect the json file to be removed, but got %v", err)
+	}
+	// opening the migrated database again keeps the values
+	if s, err = NewKVFileStorage(file); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := s.Load("foo"); err != nil || string(b) != "bar" {
+		t.Errorf("Expect %s, but got %s (%v)", "bar", string(b), err)
+	}
+}
+
+func TestNewClientWithStorage(t *testing.T) {
+	storage := NewMemoryStorage()
+	c, err := NewClientWithStorage("", "test-token", "*", storage, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.updateState(&SyncState{SyncToken: "token1", FullSync: true, Items: []Item{newTestItem("1", "100", "")}})
+	if err = c.writeCache(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := NewClientWithStorage("", "test-token", "*", storage, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.SyncToken != "token1" {
+		t.Errorf("Expect %s, but got %s", "token1", restored.SyncToken)
+	}
+	if item := restored.Item.Resolve("1"); item == nil {
+		t.Error("Expect cached item, but got nil")
+	}
+}
+
+func TestMigrateLegacyCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	token := "test-token"
+	ioutil.WriteFile(path.Join(dir, token+".json"), []byte(`{"items": [{"id": 1, "content": "foo"}]}`), 0644)
+	ioutil.WriteFile(path.Join(dir, token+".sync"), []byte("token1"), 0644)
+
+	c, err := NewClient("", token, "*", dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.SyncToken != "token1" || c.Item.Resolve("1") == nil {
+		t.Errorf("Expect migrated cache, but got %s, %v", c.SyncToken, c.Item.GetAll())
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the state, the sync token and the lock file
+	if len(files) != 3 {
+		t.Errorf("Expect %d, but got %d", 3, len(files))
+	}
+	for _, f := range files {
+		if strings.Contains(f.Name(), token) {
+			t.Errorf("Expect file names not to contain the token, but got %s", f.Name())
+		}
+		if f.Mode().Perm() != 0600 {
+			t.Errorf("Expect %v, but got %v", os.FileMode(0600), f.Mode().Perm())
+		}
+	}
+}
+
+func TestFileStorageSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(path.Join(dir, "foo"), []byte("old"), 0644)
+	if err = s.Save("foo", []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no temporary files remain
+	if len(files) != 1 {
+		t.Errorf("Expect %d, but got %d", 1, len(files))
+	}
+	if files[0].Mode().Perm() != 0600 {
+		t.Errorf("Expect %v, but got %v", os.FileMode(0600), files[0].Mode().Perm())
+	}
+	if b, err := s.Load("foo"); err != nil || string(b) != "new" {
+		t.Errorf("Expect %s, but got %s (%v)", "new", string(b), err)
+	}
+}
+
+func TestStorageLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fs, err := NewFileStorage(path.Join(dir, "files"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv, err := NewKVFileStorage(path.Join(dir, "kv", "cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []Locker{fs, kv} {
+		unlock, err := s.Lock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		locked := make(chan struct{})
+		go func() {
+			unlock, err := s.Lock()
+			if err != nil {
+				t.Error(err)
+			} else {
+				unlock()
+			}
+			close(locked)
+		}()
+		select {
+		case <-locked:
+			t.Errorf("%T: Expect to wait for unlock, but locked", s)
+		case <-time.After(100 * time.Millisecond):
+		}
+		if err = unlock(); err != nil {
+			t.Error(err)
+		}
+		select {
+		case <-locked:
+		case <-time.After(5 * time.Second):
+			t.Errorf("%T: Expect to lock after unlock, but timed out", s)
+		}
+	}
+}
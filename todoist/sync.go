@@ -1,24 +1,34 @@
 package todoist
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type SyncState struct {
-	SyncToken string `json:"sync_token"`
-	FullSync  bool   `json:"full_sync"`
-	// User User `json:"user"`
-	Projects     []Project `json:"projects"`
-	ProjectNotes []Note    `json:"project_notes"`
-	Items        []Item    `json:"items"`
-	Notes        []Note    `json:"notes"`
-	Labels       []Label   `json:"labels"`
-	Filters      []Filter  `json:"filters"`
-	// DayOrders struct {} `json:"day_orders"`
-	// DayOrdersTimestamp string `json:"day_orders_timestamp"`
-	Reminders []Reminder `json:"reminders"`
-	// Collaborators []interface{} `json:"collaborators"`
-	// CollaboratorStates []CollaboratorState `json:"collaborator_states"`
-	// LiveNotifications []LiveNotification `json:"live_notifications"`
-	// LiveNotificationsLastReadID int `json:"live_notifications_last_read_id"`
+	SyncToken                   string              `json:"sync_token"`
+	FullSync                    bool                `json:"full_sync"`
+	User                        *User               `json:"user,omitempty"`
+	Projects                    []Project           `json:"projects"`
+	ProjectNotes                []Note              `json:"project_notes"`
+	Items                       []Item              `json:"items"`
+	Notes                       []Note              `json:"notes"`
+	Labels                      []Label             `json:"labels"`
+	Filters                     []Filter            `json:"filters"`
+	DayOrders                   map[ID]int          `json:"day_orders"`
+	DayOrdersTimestamp          string              `json:"day_orders_timestamp"`
+	Reminders                   []Reminder          `json:"reminders"`
+	Sections                    []Section           `json:"sections"`
+	Collaborators               []Collaborator      `json:"collaborators"`
+	CollaboratorStates          []CollaboratorState `json:"collaborator_states"`
+	LiveNotifications           []LiveNotification  `json:"live_notifications"`
+	LiveNotificationsLastReadID ID                  `json:"live_notifications_last_read_id"`
 	// Locations []interface{} `json:"locations"`
-	// TempIDMapping struct {} `json:"temp_id_mapping"`
+	TempIDMapping TempIDMapping            `json:"temp_id_mapping"`
+	SyncStatus    map[UUID]json.RawMessage `json:"sync_status,omitempty"`
 }
 
 type Command struct {
@@ -27,3 +37,157 @@ type Command struct {
 	UUID   UUID        `json:"uuid"`
 	TempID ID          `json:"temp_id"`
 }
+
+// CommandError describes a command rejected by the server.
+type CommandError struct {
+	Command Command `json:"-"`
+	// ID is the rejected id of a command for several ids, such as item_complete with ids.
+	ID        ID     `json:"-"`
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"error"`
+	HTTPCode  int    `json:"http_code"`
+}
+
+func (e CommandError) Error() string {
+	if !e.ID.IsZero() {
+		return fmt.Sprintf("%s failed with error code %d: %s, uuid: %s, id: %s",
+			e.Command.Type, e.ErrorCode, e.Message, e.Command.UUID, e.ID)
+	}
+	return fmt.Sprintf("%s failed with error code %d: %s, uuid: %s, args: %v",
+		e.Command.Type, e.ErrorCode, e.Message, e.Command.UUID, e.Command.Args)
+}
+
+// SyncError is returned when some of the synced commands are rejected by the server.
+// Other commands in the same request are applied successfully.
+type SyncError struct {
+	Errors []CommandError
+}
+
+func (e *SyncError) Error() string {
+	var arr []string
+	for _, ce := range e.Errors {
+		arr = append(arr, ce.Error())
+	}
+	return fmt.Sprintf("%d command(s) failed: %s", len(e.Errors), strings.Join(arr, "; "))
+}
+
+// newSyncError builds a SyncError from sync_status of the response.
+// It returns nil when all the commands succeeded.
+// The status of a command for several ids is a map from the ids to their statuses, and only the failed ids are reported.
+func newSyncError(commands []Command, status map[UUID]json.RawMessage) error {
+	var errs []CommandError
+	for _, command := range commands {
+		raw, ok := status[command.UUID]
+		if !ok {
+			continue
+		}
+		if isStatusOK(raw) {
+			continue
+		}
+		if statuses, ok := decodeStatusByID(raw); ok {
+			var ids []string
+			for id := range statuses {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			for _, id := range ids {
+				if !isStatusOK(statuses[id]) {
+					errs = append(errs, newCommandError(command, ID(id), statuses[id]))
+				}
+			}
+			continue
+		}
+		errs = append(errs, newCommandError(command, "", raw))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &SyncError{Errors: errs}
+}
+
+func isStatusOK(raw json.RawMessage) bool {
+	var s string
+	return json.Unmarshal(raw, &s) == nil && s == "ok"
+}
+
+// decodeStatusByID decodes the status of a command for several ids, like {"1": "ok", "2": {"error_code": 22, ...}}.
+// It fails for the status of an error, which is an object too.
+func decodeStatusByID(raw json.RawMessage) (map[string]json.RawMessage, bool) {
+	var statuses map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &statuses); err != nil || len(statuses) == 0 {
+		return nil, false
+	}
+	for _, key := range []string{"error", "error_code"} {
+		if _, ok := statuses[key]; ok {
+			return nil, false
+		}
+	}
+	return statuses, true
+}
+
+func newCommandError(command Command, id ID, raw json.RawMessage) CommandError {
+	ce := CommandError{Command: command, ID: id}
+	if err := json.Unmarshal(raw, &ce); err != nil {
+		ce.Message = string(raw)
+	}
+	return ce
+}
+
+// TempIDMapping maps temporary ids of created resources to the ids assigned by the server.
+type TempIDMapping map[ID]ID
+
+// Resolve returns the real id for the given temporary id.
+// The id is returned as is when it is not mapped.
+func (m TempIDMapping) Resolve(id ID) ID {
+	if real, ok := m[id]; ok {
+		return real
+	}
+	return id
+}
+
+func (m TempIDMapping) resolveAll(ids []ID) []ID {
+	if len(ids) == 0 {
+		return ids
+	}
+	res := make([]ID, len(ids))
+	for i, id := range ids {
+		res[i] = m.Resolve(id)
+	}
+	return res
+}
+
+// replaceArgs rewrites the temporary ids referenced in command args.
+// Args are converted into a generic json structure because each command has its own shape.
+func (m TempIDMapping) replaceArgs(args interface{}) (interface{}, error) {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var v interface{}
+	if err = decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return m.replaceValue(v), nil
+}
+
+func (m TempIDMapping) replaceValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		if real, ok := m[ID(t)]; ok {
+			return real
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = m.replaceValue(e)
+		}
+	case map[string]interface{}:
+		res := map[string]interface{}{}
+		for k, e := range t {
+			res[m.Resolve(ID(k)).String()] = m.replaceValue(e)
+		}
+		return res
+	}
+	return v
+}
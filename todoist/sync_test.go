@@ -0,0 +1,105 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTempIDMapping_Resolve(t *testing.T) {
+	tempID := ID("df43406d-db7e-4ea5-b3b4-c822ccdab3bf")
+	m := TempIDMapping{tempID: ID("1000000")}
+	if id := m.Resolve(tempID); id != ID("1000000") {
+		t.Errorf("Expect %s, but got %s", "1000000", id)
+	}
+	if id := m.Resolve(ID("2000000")); id != ID("2000000") {
+		t.Errorf("Expect %s, but got %s", "2000000", id)
+	}
+}
+
+func TestTempIDMapping_replaceArgs(t *testing.T) {
+	tempID := ID("df43406d-db7e-4ea5-b3b4-c822ccdab3bf")
+	m := TempIDMapping{tempID: ID("1000000")}
+	tests := []struct {
+		args interface{}
+		s    string
+	}{
+		{
+			map[string]ID{"id": tempID},
+			`{"id":1000000}`,
+		},
+		{
+			map[string]map[ID]int{"id_order_mapping": {tempID: 1}},
+			`{"id_order_mapping":{"1000000":1}}`,
+		},
+		{
+			Item{Content: "foo", ProjectID: tempID, Labels: []ID{tempID, "2000000"}},
+			`{"completed_date":null,"content":"foo","date_added":null,"due":{"date":null,"is_recurring":false,"lang":"","string":"","timezone":""},"labels":[1000000,2000000],"project_id":1000000}`,
+		},
+	}
+	for _, test := range tests {
+		args, err := m.replaceArgs(test.args)
+		if err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		b, err := json.Marshal(args)
+		if err != nil || string(b) != test.s {
+			t.Errorf("Expect %s, but got %s", test.s, string(b))
+		}
+	}
+}
+
+func TestNewSyncError(t *testing.T) {
+	commands := []Command{
+		{Type: "item_add", UUID: GenerateUUID(), TempID: GenerateTempID()},
+		{Type: "item_delete", UUID: GenerateUUID()},
+	}
+	status := map[UUID]json.RawMessage{
+		commands[0].UUID: json.RawMessage(`"ok"`),
+		commands[1].UUID: json.RawMessage(`{"error_code": 22, "error": "Item not found", "http_code": 404}`),
+	}
+	err := newSyncError(commands, status)
+	syncErr, ok := err.(*SyncError)
+	if !ok {
+		t.Fatalf("Expect *SyncError, but got %#v", err)
+	}
+	if len(syncErr.Errors) != 1 {
+		t.Fatalf("Expect %d, but got %d", 1, len(syncErr.Errors))
+	}
+	ce := syncErr.Errors[0]
+	if ce.Command.UUID != commands[1].UUID || ce.ErrorCode != 22 || ce.Message != "Item not found" || ce.HTTPCode != 404 {
+		t.Errorf("Unexpect command error: %#v", ce)
+	}
+
+	delete(status, commands[1].UUID)
+	if err = newSyncError(commands, status); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+}
+
+func TestNewSyncError_statusByID(t *testing.T) {
+	commands := []Command{
+		{Type: "item_complete", UUID: GenerateUUID()},
+		{Type: "item_delete", UUID: GenerateUUID()},
+	}
+	status := map[UUID]json.RawMessage{
+		commands[0].UUID: json.RawMessage(`{"1": "ok", "2": "ok"}`),
+		commands[1].UUID: json.RawMessage(`{"3": "ok", "4": {"error_code": 22, "error": "Item not found", "http_code": 404}}`),
+	}
+	err := newSyncError(commands, status)
+	syncErr, ok := err.(*SyncError)
+	if !ok {
+		t.Fatalf("Expect *SyncError, but got %#v", err)
+	}
+	if len(syncErr.Errors) != 1 {
+		t.Fatalf("Expect %d, but got %d", 1, len(syncErr.Errors))
+	}
+	ce := syncErr.Errors[0]
+	if ce.Command.UUID != commands[1].UUID || ce.ID != "4" || ce.ErrorCode != 22 || ce.Message != "Item not found" {
+		t.Errorf("Unexpect command error: %#v", ce)
+	}
+
+	delete(status, commands[1].UUID)
+	if err = newSyncError(commands, status); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+}
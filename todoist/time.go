@@ -33,31 +33,70 @@ type Time struct {
 	time.Time
 }
 
+// floating is the location of full-day and floating dates which are not localized yet.
+// They are decoded without the timezone of the account, and the client localizes them in it.
+var floating = time.FixedZone("", 0)
+
+// utcWall is UTC for full-day and floating dates, so that they are not mistaken for instants in time.UTC.
+var utcWall = time.FixedZone("UTC", 0)
+
+// wallLocation returns the location for full-day and floating dates in the timezone.
+func wallLocation(loc *time.Location) *time.Location {
+	if loc == time.UTC {
+		return utcWall
+	}
+	return loc
+}
+
+// Today returns the end of today in the local timezone.
+//
+// Deprecated: Use TodayIn with Client.Location to count days in the timezone of the account.
 func Today() Time {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 1, time.Local)
+	return TodayIn(time.Local)
+}
+
+// TodayIn returns the end of today in the timezone.
+func TodayIn(loc *time.Location) Time {
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 1, loc)
 	return Time{today.UTC()}
 }
 
+// Next7Days returns the end of the 7th day from today in the local timezone.
+//
+// Deprecated: Use Next7DaysIn with Client.Location to count days in the timezone of the account.
 func Next7Days() Time {
-	d := time.Now().Add(6 * 24 * time.Hour)
-	days := time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 1, time.Local)
+	return Next7DaysIn(time.Local)
+}
+
+// Next7DaysIn returns the end of the 7th day from today in the timezone.
+func Next7DaysIn(loc *time.Location) Time {
+	d := time.Now().In(loc).Add(6 * 24 * time.Hour)
+	days := time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 1, loc)
 	return Time{days.UTC()}
 }
 
+// Parse parses the date of the api with full-day and floating dates in the local timezone.
+//
+// Deprecated: Use ParseIn with Client.Location to parse them in the timezone of the account.
 func Parse(value string) (Time, error) {
+	return ParseIn(value, time.Local)
+}
+
+// ParseIn parses the date of the api. Full-day and floating dates are in the timezone,
+// and dates with the timezone are in UTC.
+func ParseIn(value string, loc *time.Location) (Time, error) {
 	var t time.Time
 	var err error
 	for _, layout := range []string{dateLayout, datetimeLayout} {
-		// FIXME: refer to user.tz_info.timezone
-		if t, err = time.ParseInLocation(layout, value, time.Local); err == nil {
+		if t, err = time.ParseInLocation(layout, value, wallLocation(loc)); err == nil {
 			return Time{t}, nil
 		}
 	}
 	if t, err = time.Parse(datetimeTzLayout, value); err != nil {
 		return Time{}, err
 	}
-	return Time{t}, nil
+	return Time{t.UTC()}, nil
 }
 
 func (t Time) Equal(u Time) bool {
@@ -72,8 +111,34 @@ func (t Time) After(u Time) bool {
 	return t.Time.After(u.Time)
 }
 
+// In returns the time in the timezone.
+// Times in UTC are instants, and the others are full-day or floating dates,
+// which keep their clock in any timezone.
+func (t Time) In(loc *time.Location) Time {
+	if t.IsZero() {
+		return t
+	}
+	if t.Time.Location() == time.UTC {
+		return Time{t.Time.In(loc)}
+	}
+	return t.localize(loc)
+}
+
+// Local returns the time in the local timezone like In.
+//
+// Deprecated: Use In with Client.Location to show the time in the timezone of the account.
 func (t Time) Local() Time {
-	return Time{t.Time.Local()}
+	return t.In(time.Local)
+}
+
+// localize moves full-day and floating dates to the timezone keeping their clock.
+// Times in UTC are kept as is, so that they are encoded with the timezone again.
+func (t Time) localize(loc *time.Location) Time {
+	loc = wallLocation(loc)
+	if t.IsZero() || t.Time.Location() == time.UTC || t.Time.Location() == loc {
+		return t
+	}
+	return Time{time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)}
 }
 
 func (t Time) IsFullDay() bool {
@@ -95,11 +160,16 @@ func (t Time) MarshalJSON() ([]byte, error) {
 }
 
 func (t *Time) UnmarshalJSON(b []byte) (err error) {
+	// some resources like live notifications use unix timestamps.
+	if n, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+		*t = Time{time.Unix(n, 0).UTC()}
+		return nil
+	}
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		*t = Time{time.Time{}} // null value
 	} else {
-		*t, err = Parse(s)
+		*t, err = ParseIn(s, floating)
 		if err != nil {
 			return err
 		}
@@ -107,15 +177,18 @@ func (t *Time) UnmarshalJSON(b []byte) (err error) {
 	return nil
 }
 
+// String formats the time in the local timezone if it is an instant in UTC.
+// Times moved to a timezone by In, such as the timezone of the account, are formatted in it.
 func (t Time) String() string {
 	if t.IsZero() {
 		return ""
 	}
+	t = t.In(time.Local)
 	layout := localDatetimeLayout
 	if t.IsFullDay() {
 		layout = localDateLayout
 	}
-	return t.Time.Local().Format(layout)
+	return t.Time.Format(layout)
 }
 
 func (t Time) ColorString() string {
@@ -32,7 +32,7 @@ var testTimes = []struct {
 
 func TestParse(t *testing.T) {
 	for i, tt := range testTimes {
-		tim, err := Parse(tt.s)
+		tim, err := ParseIn(tt.s, time.Local)
 		if !reflect.DeepEqual(err, tt.e) {
 			t.Errorf("%d. %q error mismatch:\n exp=%s\n got=%s\n\n", i, tt.s, tt.e, err)
 		} else if tt.e == nil && !tim.Equal(tt.v) {
@@ -60,7 +60,7 @@ func TestTime_UnmarshalJSON(t *testing.T) {
 		err := v.UnmarshalJSON([]byte(strconv.Quote(test.s)))
 		if !reflect.DeepEqual(err, test.e) {
 			t.Errorf("Expect %s, but got %s", test.e, err)
-		} else if test.e == nil && !v.Equal(test.v) {
+		} else if test.e == nil && !v.In(time.Local).Equal(test.v) {
 			t.Errorf("Expect %s, but got %s", test.v, v)
 		}
 	}
@@ -88,8 +88,69 @@ func TestTimeJson(t *testing.T) {
 		if err := json.Unmarshal([]byte(strconv.Quote(tt.s)), &um); err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 		}
-		if !um.Equal(tt.v) {
+		if !um.In(time.Local).Equal(tt.v) {
 			t.Errorf("mismatch:\n exp=%#v\n got=%#v\n\n", tt.v, um)
 		}
 	}
 }
+
+func TestTime_In(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+
+	tim, err := ParseIn("2014-09-26", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := time.Date(2014, 9, 26, 0, 0, 0, 0, loc); !tim.Time.Equal(expect) {
+		t.Errorf("Expect %s, but got %s", expect, tim)
+	}
+	if tim.String() != "2014-09-26(Fri)" {
+		t.Errorf("Expect %s, but got %s", "2014-09-26(Fri)", tim.String())
+	}
+	// floating dates keep the clock, and instants are converted.
+	var floating Time
+	if err = json.Unmarshal([]byte(`"2014-09-26T08:25:05"`), &floating); err != nil {
+		t.Fatal(err)
+	}
+	if expect := time.Date(2014, 9, 26, 8, 25, 5, 0, loc); !floating.In(loc).Time.Equal(expect) {
+		t.Errorf("Expect %s, but got %s", expect, floating.In(loc))
+	}
+	utc := Time{time.Date(2014, 9, 26, 20, 0, 0, 0, time.UTC)}
+	if utc.In(loc).String() != "2014-09-27(Sat) 05:00" {
+		t.Errorf("Expect %s, but got %s", "2014-09-27(Sat) 05:00", utc.In(loc).String())
+	}
+
+	now := time.Now().In(loc)
+	today := TodayIn(loc).In(loc)
+	if today.Year() != now.Year() || today.YearDay() != now.YearDay() {
+		t.Errorf("Expect today in %s, but got %s", loc, today)
+	}
+}
+
+func TestTime_StringLocal(t *testing.T) {
+	// instants are formatted in the local timezone, like Local.
+	utc := Time{time.Date(2014, 9, 26, 20, 0, 0, 0, time.UTC)}
+	if expect := utc.Time.In(time.Local).Format(localDatetimeLayout); utc.String() != expect || utc.Local().String() != expect {
+		t.Errorf("Expect %s, but got %s and %s", expect, utc.String(), utc.Local().String())
+	}
+	tim, err := Parse("2014-09-26")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tim.Time.Equal(time.Date(2014, 9, 26, 0, 0, 0, 0, time.Local)) || tim.String() != "2014-09-26(Fri)" {
+		t.Errorf("Expect %s, but got %s", "2014-09-26(Fri)", tim)
+	}
+	if !Today().Equal(TodayIn(time.Local)) || !Next7Days().Equal(Next7DaysIn(time.Local)) {
+		t.Errorf("Expect the end of days in the local timezone, but got %s and %s", Today(), Next7Days())
+	}
+}
+
+func TestTime_UnmarshalJSONUnix(t *testing.T) {
+	var v Time
+	if err := v.UnmarshalJSON([]byte("1577836800")); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if expect := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC); !v.Time.Equal(expect) {
+		t.Errorf("Expect %s, but got %s", expect, v)
+	}
+}
@@ -0,0 +1,82 @@
+package todoist
+
+import (
+	"time"
+)
+
+type User struct {
+	ID                ID      `json:"id"`
+	Email             string  `json:"email"`
+	FullName          string  `json:"full_name"`
+	InboxProject      ID      `json:"inbox_project"`
+	TeamInbox         ID      `json:"team_inbox"`
+	TzInfo            TzInfo  `json:"tz_info"`
+	StartDay          int     `json:"start_day"`
+	StartPage         string  `json:"start_page"`
+	NextWeek          int     `json:"next_week"`
+	WeekendStartDay   int     `json:"weekend_start_day"`
+	DaysOff           []int   `json:"days_off"`
+	DateFormat        int     `json:"date_format"`
+	TimeFormat        int     `json:"time_format"`
+	SortOrder         int     `json:"sort_order"`
+	Lang              string  `json:"lang"`
+	Karma             float64 `json:"karma"`
+	KarmaTrend        string  `json:"karma_trend"`
+	DailyGoal         int     `json:"daily_goal"`
+	WeeklyGoal        int     `json:"weekly_goal"`
+	IsPremium         bool    `json:"is_premium"`
+	PremiumUntil      string  `json:"premium_until"`
+	IsBizAdmin        bool    `json:"is_biz_admin"`
+	BusinessAccountID ID      `json:"business_account_id"`
+	DefaultReminder   string  `json:"default_reminder"`
+	AutoReminder      int     `json:"auto_reminder"`
+	JoinDate          string  `json:"join_date"`
+	ImageID           string  `json:"image_id"`
+	Theme             int     `json:"theme"`
+}
+
+type TzInfo struct {
+	Timezone  string  `json:"timezone"`
+	GmtString string  `json:"gmt_string"`
+	Hours     int     `json:"hours"`
+	Minutes   int     `json:"minutes"`
+	IsDst     IntBool `json:"is_dst"`
+}
+
+// Location returns the timezone of the account.
+// If the timezone is not in the tz database of the system, the fixed offset is used instead.
+func (u User) Location() *time.Location {
+	if len(u.TzInfo.Timezone) != 0 {
+		if loc, err := time.LoadLocation(u.TzInfo.Timezone); err == nil {
+			return loc
+		}
+	}
+	offset := u.TzInfo.Hours*60*60 + u.TzInfo.Minutes*60
+	name := u.TzInfo.Timezone
+	if len(name) == 0 {
+		name = u.TzInfo.GmtString
+	}
+	return time.FixedZone(name, offset)
+}
+
+func (u User) String() string {
+	if len(u.FullName) == 0 {
+		return u.Email
+	}
+	return u.FullName
+}
+
+type UserClient struct {
+	*Client
+}
+
+// Get returns the user of the account, or nil before it is synced.
+func (c UserClient) Get() *User {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.syncState.User == nil {
+		return nil
+	}
+	user := *c.syncState.User
+	return &user
+}
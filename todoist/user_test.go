@@ -0,0 +1,79 @@
+package todoist
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestUser_Location(t *testing.T) {
+	tests := []struct {
+		tz     TzInfo
+		offset int
+	}{
+		{TzInfo{Timezone: "UTC"}, 0},
+		{TzInfo{Timezone: "Unknown/Zone", Hours: 9}, 9 * 60 * 60},
+		{TzInfo{GmtString: "-03:30", Hours: -3, Minutes: -30}, -(3*60 + 30) * 60},
+	}
+	for _, test := range tests {
+		_, offset := time.Date(2020, 1, 1, 0, 0, 0, 0, User{TzInfo: test.tz}.Location()).Zone()
+		if offset != test.offset {
+			t.Errorf("%v: Expect %d, but got %d", test.tz, test.offset, offset)
+		}
+	}
+}
+
+func TestClient_updateStateLocation(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	// the user comes after the items, but the dates are localized in the timezone of the user.
+	b := []byte(`{
+		"items": [{"id": 1, "content": "foo", "due": {"date": "2020-01-01"}}],
+		"user": {"id": 2, "tz_info": {"timezone": "", "gmt_string": "+09:00", "hours": 9, "minutes": 0, "is_dst": 0}}
+	}`)
+	var state SyncState
+	if err := json.Unmarshal(b, &state); err != nil {
+		t.Fatal(err)
+	}
+	c.updateState(&state)
+	expect := time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("+09:00", 9*60*60))
+	if item := c.Item.Resolve("1"); item == nil || !item.Due.Date.Time.Equal(expect) {
+		t.Errorf("Expect %s, but got %v", expect, item)
+	}
+	// floating dates keep the clock when the timezone is changed.
+	c.updateState(&SyncState{User: &User{ID: "2", TzInfo: TzInfo{GmtString: "-05:00", Hours: -5}}})
+	expect = time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("-05:00", -5*60*60))
+	if item := c.Item.Resolve("1"); item == nil || !item.Due.Date.Time.Equal(expect) {
+		t.Errorf("Expect %s, but got %v", expect, item)
+	}
+	// another client is not affected.
+	other := newTestClient(t, "")
+	defer os.RemoveAll(other.CacheDir)
+	if other.Location() != time.Local {
+		t.Errorf("Expect %s, but got %s", time.Local, other.Location())
+	}
+}
+
+func TestClient_updateStateUser(t *testing.T) {
+	c := newTestClient(t, "")
+	defer os.RemoveAll(c.CacheDir)
+	if c.User.Get() != nil {
+		t.Errorf("Expect nil, but got %v", c.User.Get())
+	}
+	c.updateState(&SyncState{
+		FullSync: true,
+		User:     &User{ID: "1", FullName: "foo", TzInfo: TzInfo{Timezone: "UTC"}},
+	})
+	// incremental syncs without the user keep it.
+	c.updateState(&SyncState{})
+	if user := c.User.Get(); user == nil || user.FullName != "foo" {
+		t.Errorf("Unexpect user: %v", user)
+	}
+	if c.Location().String() != "UTC" {
+		t.Errorf("Expect %s, but got %s", "UTC", c.Location())
+	}
+	if c.snapshot().User == nil {
+		t.Error("Expect the user in the cache, but got nil")
+	}
+}